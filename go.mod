@@ -2,7 +2,4 @@ module github.com/Sush1sui
 
 go 1.24
 
-require (
-	github.com/joho/godotenv v1.5.1
-	golang.org/x/text v0.27.0
-)
+require github.com/joho/godotenv v1.5.1
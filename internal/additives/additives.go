@@ -0,0 +1,266 @@
+package additives
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Sush1sui/internal/ingredients"
+)
+
+// Functional classes.
+const (
+	Colorant         = "colorant"
+	Preservative     = "preservative"
+	Antioxidant      = "antioxidant"
+	Sweetener        = "sweetener"
+	Emulsifier       = "emulsifier"
+	Stabilizer       = "stabilizer"
+	Thickener        = "thickener"
+	AcidityRegulator = "acidity regulator"
+	FlavorEnhancer   = "flavor enhancer"
+	RaisingAgent     = "raising agent"
+	AntiCaking       = "anti-caking agent"
+	GlazingAgent     = "glazing agent"
+	Humectant        = "humectant"
+)
+
+// Concern levels, lowest first.
+const (
+	None     = "none"
+	Low      = "low"
+	Moderate = "moderate"
+	High     = "high"
+)
+
+var concernRank = map[string]int{None: 0, Low: 1, Moderate: 2, High: 3}
+
+// Additive is a food additive by its E-number; the INS code is the number
+// without the "E".
+type Additive struct {
+	Code    string
+	Name    string
+	Class   string
+	Concern string
+	// Names are lowercase common names the additive is listed under.
+	Names []string
+}
+
+// INS returns the International Numbering System code of the additive.
+func (a Additive) INS() string {
+	return strings.TrimPrefix(a.Code, "E")
+}
+
+// Match is where an additive appears in the ingredient text. Start and End
+// are character (rune) offsets. Function is the class the label itself
+// gives the ingredient, as in "preservative: sodium benzoate".
+type Match struct {
+	Text     string `json:"text"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Function string `json:"function,omitempty"`
+}
+
+// Detection is an additive found in an ingredient list.
+type Detection struct {
+	Code    string  `json:"code"`
+	INS     string  `json:"ins"`
+	Name    string  `json:"name"`
+	Class   string  `json:"class"`
+	Concern string  `json:"concern"`
+	Matches []Match `json:"matches"`
+}
+
+var (
+	mu     sync.RWMutex
+	byCode = map[string]*Additive{}
+	// names matches the common names of every additive.
+	names  *regexp.Regexp
+	byName = map[string]*Additive{}
+)
+
+// codePattern matches "E211", "E 150d", "E-471" and "INS 211".
+var codePattern = regexp.MustCompile(`\b(?:e|ins)(?:\s?-?\s?|\s?no\.?\s?)(\d{3,4}[a-f]?)\b`)
+
+// vitaminBefore matches text ending in "vitamin", whose "e" followed by an
+// amount, as in "vitamin e 400 iu", is not an E-number.
+var vitaminBefore = regexp.MustCompile(`vitamin[\s-]*$`)
+
+func init() {
+	var all []string
+	for i := range additives {
+		a := &additives[i]
+		byCode[a.Code] = a
+		for _, n := range a.Names {
+			byName[n] = a
+			all = append(all, n)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return len(all[i]) > len(all[j]) })
+	quoted := make([]string, len(all))
+	for i, n := range all {
+		quoted[i] = regexp.QuoteMeta(n)
+	}
+	names = regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)s?\b`)
+}
+
+// CheckConcern reports an error for an unknown concern level.
+func CheckConcern(level string) error {
+	if _, ok := concernRank[level]; !ok {
+		return fmt.Errorf("unknown additive concern %q (available: %s, %s, %s, %s)", level, None, Low, Moderate, High)
+	}
+	return nil
+}
+
+// AtLeast reports whether concern is at or above level.
+func AtLeast(concern, level string) bool {
+	return concernRank[concern] >= concernRank[level]
+}
+
+// LoadFile overrides concern levels from a JSON file mapping E-numbers to
+// levels, e.g. {"E211": "high", "E621": "none"}.
+func LoadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded map[string]string
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for code, level := range loaded {
+		if _, ok := byCode[normalizeCode(code)]; !ok {
+			return fmt.Errorf("unknown additive %q", code)
+		}
+		if err := CheckConcern(level); err != nil {
+			return fmt.Errorf("additive %s: %w", code, err)
+		}
+	}
+	for code, level := range loaded {
+		byCode[normalizeCode(code)].Concern = level
+	}
+	return nil
+}
+
+// Lookup finds an additive by E-number or INS code ("E211", "211", "e150d").
+func Lookup(code string) (Additive, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := byCode[normalizeCode(code)]
+	if !ok {
+		return Additive{}, false
+	}
+	return *a, true
+}
+
+func normalizeCode(code string) string {
+	code = strings.ToLower(strings.Join(strings.Fields(code), ""))
+	code = strings.TrimPrefix(strings.TrimPrefix(code, "ins"), "e")
+	return "E" + strings.TrimLeft(code, "-")
+}
+
+// Detect finds the additives in an ingredient statement by E-number, INS
+// code and common name, in order of first appearance. An E-number with a
+// letter suffix that is not listed on its own ("E150e") falls back to the
+// base number.
+func Detect(statement string) []Detection {
+	text := lower(statement)
+	tree := ingredients.Parse(statement)
+
+	mu.RLock()
+	defer mu.RUnlock()
+	detections := []Detection{}
+	index := map[string]int{}
+	add := func(a *Additive, loc []int) {
+		start := utf8.RuneCountInString(text[:loc[0]])
+		m := Match{
+			Text:     statement[loc[0]:loc[1]],
+			Start:    start,
+			End:      utf8.RuneCountInString(text[:loc[1]]),
+			Function: functionAt(tree.Ingredients, start),
+		}
+		i, ok := index[a.Code]
+		if !ok {
+			i = len(detections)
+			index[a.Code] = i
+			detections = append(detections, Detection{
+				Code:    a.Code,
+				INS:     a.INS(),
+				Name:    a.Name,
+				Class:   a.Class,
+				Concern: a.Concern,
+			})
+		}
+		detections[i].Matches = append(detections[i].Matches, m)
+	}
+
+	for _, loc := range codePattern.FindAllStringSubmatchIndex(text, -1) {
+		if vitaminBefore.MatchString(text[:loc[0]]) {
+			continue
+		}
+		code := "E" + text[loc[2]:loc[3]]
+		a, ok := byCode[code]
+		if !ok {
+			a, ok = byCode[strings.TrimRight(code, "abcdef")]
+		}
+		if ok {
+			add(a, loc[:2])
+		}
+	}
+	for _, loc := range names.FindAllStringIndex(text, -1) {
+		name := text[loc[0]:loc[1]]
+		a, ok := byName[name]
+		if !ok {
+			a, ok = byName[strings.TrimSuffix(name, "s")]
+		}
+		if ok {
+			add(a, loc)
+		}
+	}
+
+	for i := range detections {
+		sort.Slice(detections[i].Matches, func(a, b int) bool {
+			return detections[i].Matches[a].Start < detections[i].Matches[b].Start
+		})
+	}
+	sort.SliceStable(detections, func(a, b int) bool {
+		return detections[a].Matches[0].Start < detections[b].Matches[0].Start
+	})
+	return detections
+}
+
+// functionAt returns the class the label gives the innermost ingredient
+// containing the offset, or an enclosing one's.
+func functionAt(list []ingredients.Ingredient, offset int) string {
+	for _, ing := range list {
+		if offset < ing.Start || offset >= ing.End {
+			continue
+		}
+		if f := functionAt(ing.Ingredients, offset); f != "" {
+			return f
+		}
+		return ing.Function
+	}
+	return ""
+}
+
+// lower lowercases rune by rune, keeping byte offsets aligned with the
+// original.
+func lower(s string) string {
+	return strings.Map(func(r rune) rune {
+		l := unicode.ToLower(r)
+		if utf8.RuneLen(l) != utf8.RuneLen(r) {
+			return r
+		}
+		return l
+	}, s)
+}
@@ -0,0 +1,122 @@
+package additives
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// codes renders detections as their codes in order.
+func codes(detections []Detection) string {
+	var out []string
+	for _, d := range detections {
+		out = append(out, d.Code)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{"Water, sugar, acid (E330), preservative: E 211, colour: E150d", "E330 E211 E150d"},
+		{"Carbonated water, citric acid, sodium benzoate, INS 621", "E330 E211 E621"},
+		{"Emulsifiers (soy lecithin, mono- and diglycerides), E-471", "E322 E471"},
+		// the same additive by code and name is one detection
+		{"Citric acid (E330)", "E330"},
+		{"Yellow 5, FD&C Yellow No. 5", "E102"},
+		{"Sugar, salt, E999, E1", ""},
+		// vitamin E with an amount is not an E-number
+		{"Soybean oil, vitamin E 400 IU, Vitamin-E 306", ""},
+		{"Vitamin E (tocopherols), E306", "E306"},
+		{"Sugar", ""},
+	}
+	for _, tt := range tests {
+		if got := codes(Detect(tt.statement)); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.statement, got, tt.want)
+		}
+	}
+}
+
+func TestDetectMatches(t *testing.T) {
+	statement := "Água, sweeteners: (sucralose), preservative: sodium benzoate, E211"
+	got := Detect(statement)
+	if codes(got) != "E955 E211" {
+		t.Fatalf("Detect = %q", codes(got))
+	}
+	runes := []rune(statement)
+	for _, d := range got {
+		for _, m := range d.Matches {
+			if string(runes[m.Start:m.End]) != m.Text {
+				t.Errorf("%s match %+v does not index the statement by rune", d.Code, m)
+			}
+		}
+	}
+	if f := got[0].Matches[0].Function; f != "sweeteners" {
+		t.Errorf("sucralose function = %q, want the enclosing sweetener", f)
+	}
+	benzoate := got[1]
+	if len(benzoate.Matches) != 2 || benzoate.Matches[0].Function != "preservative" || benzoate.Matches[1].Function != "" {
+		t.Errorf("sodium benzoate matches = %+v", benzoate.Matches)
+	}
+	if benzoate.INS != "211" || benzoate.Class != Preservative || benzoate.Concern != Moderate {
+		t.Errorf("sodium benzoate = %+v", benzoate)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, code := range []string{"E211", "211", "e 211", "INS211", "E-211"} {
+		if a, ok := Lookup(code); !ok || a.Code != "E211" {
+			t.Errorf("Lookup(%q) = %+v, %v", code, a, ok)
+		}
+	}
+	if a, ok := Lookup("e150d"); !ok || a.Code != "E150d" {
+		t.Errorf("Lookup(e150d) = %+v, %v", a, ok)
+	}
+	if _, ok := Lookup("E999"); ok {
+		t.Error("E999 found")
+	}
+}
+
+func TestConcern(t *testing.T) {
+	if !AtLeast(High, Moderate) || !AtLeast(Low, Low) || AtLeast(None, Low) {
+		t.Error("AtLeast does not follow None < Low < Moderate < High")
+	}
+	if err := CheckConcern("severe"); err == nil {
+		t.Error("unknown concern accepted")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "concerns.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	original, _ := Lookup("E621")
+	defer LoadFile(write(`{"E621": "` + original.Concern + `"}`))
+
+	if err := LoadFile(write(`{"621": "high"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := Lookup("E621"); a.Concern != High {
+		t.Errorf("E621 concern = %q after loading high", a.Concern)
+	}
+	if d := Detect("msg"); len(d) != 1 || d[0].Concern != High {
+		t.Errorf("Detect does not report the loaded concern: %+v", d)
+	}
+
+	for _, bad := range []string{`{"E999": "low"}`, `{"E621": "severe"}`, `{`} {
+		if err := LoadFile(write(bad)); err == nil {
+			t.Errorf("loaded %s", bad)
+		}
+	}
+	if a, _ := Lookup("E621"); a.Concern != High {
+		t.Errorf("a rejected file changed E621 to %q", a.Concern)
+	}
+}
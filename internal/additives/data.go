@@ -0,0 +1,162 @@
+package additives
+
+// additives are the commonly listed EU/Codex food additives. Default
+// concern levels follow EFSA re-evaluations and IARC classifications:
+// High for additives banned or restricted in the EU or linked to
+// carcinogenic by-products, Moderate for the Southampton colours and
+// additives with low acceptable daily intakes or ongoing reviews.
+var additives = []Additive{
+	// Colours
+	{"E100", "Curcumin", Colorant, None, []string{"curcumin", "turmeric extract"}},
+	{"E101", "Riboflavin", Colorant, None, nil},
+	{"E102", "Tartrazine", Colorant, Moderate, []string{"tartrazine", "yellow 5", "fd&c yellow 5", "fd&c yellow no. 5"}},
+	{"E104", "Quinoline yellow", Colorant, Moderate, []string{"quinoline yellow"}},
+	{"E110", "Sunset yellow FCF", Colorant, Moderate, []string{"sunset yellow", "yellow 6", "fd&c yellow 6", "fd&c yellow no. 6"}},
+	{"E120", "Carmine", Colorant, Low, []string{"carmine", "cochineal", "carminic acid"}},
+	{"E122", "Azorubine", Colorant, Moderate, []string{"azorubine", "carmoisine"}},
+	{"E124", "Ponceau 4R", Colorant, Moderate, []string{"ponceau 4r", "cochineal red a"}},
+	{"E127", "Erythrosine", Colorant, High, []string{"erythrosine", "red 3", "fd&c red 3", "fd&c red no. 3"}},
+	{"E129", "Allura red AC", Colorant, Moderate, []string{"allura red", "red 40", "fd&c red 40", "fd&c red no. 40"}},
+	{"E131", "Patent blue V", Colorant, Low, []string{"patent blue"}},
+	{"E132", "Indigotine", Colorant, Low, []string{"indigotine", "indigo carmine", "blue 2", "fd&c blue 2"}},
+	{"E133", "Brilliant blue FCF", Colorant, Low, []string{"brilliant blue", "blue 1", "fd&c blue 1", "fd&c blue no. 1"}},
+	{"E140", "Chlorophylls", Colorant, None, []string{"chlorophyll"}},
+	{"E141", "Copper chlorophyllins", Colorant, None, []string{"copper chlorophyllin"}},
+	{"E150a", "Plain caramel", Colorant, None, []string{"plain caramel"}},
+	{"E150b", "Caustic sulphite caramel", Colorant, Low, nil},
+	{"E150c", "Ammonia caramel", Colorant, Moderate, []string{"ammonia caramel"}},
+	{"E150d", "Sulphite ammonia caramel", Colorant, Moderate, []string{"sulphite ammonia caramel", "sulfite ammonia caramel"}},
+	{"E151", "Brilliant black BN", Colorant, Moderate, []string{"brilliant black"}},
+	{"E153", "Vegetable carbon", Colorant, Low, []string{"vegetable carbon"}},
+	{"E160a", "Carotenes", Colorant, None, []string{"beta-carotene", "beta carotene"}},
+	{"E160b", "Annatto", Colorant, Low, []string{"annatto", "bixin", "norbixin"}},
+	{"E160c", "Paprika extract", Colorant, None, []string{"paprika extract", "paprika oleoresin"}},
+	{"E162", "Beetroot red", Colorant, None, []string{"beetroot red", "betanin"}},
+	{"E163", "Anthocyanins", Colorant, None, []string{"anthocyanin"}},
+	{"E171", "Titanium dioxide", Colorant, High, []string{"titanium dioxide"}},
+	{"E172", "Iron oxides", Colorant, None, []string{"iron oxide"}},
+
+	// Preservatives
+	{"E200", "Sorbic acid", Preservative, None, []string{"sorbic acid"}},
+	{"E202", "Potassium sorbate", Preservative, None, []string{"potassium sorbate"}},
+	{"E210", "Benzoic acid", Preservative, Moderate, []string{"benzoic acid"}},
+	{"E211", "Sodium benzoate", Preservative, Moderate, []string{"sodium benzoate"}},
+	{"E212", "Potassium benzoate", Preservative, Moderate, []string{"potassium benzoate"}},
+	{"E213", "Calcium benzoate", Preservative, Moderate, []string{"calcium benzoate"}},
+	{"E220", "Sulphur dioxide", Preservative, Moderate, []string{"sulphur dioxide", "sulfur dioxide"}},
+	{"E221", "Sodium sulphite", Preservative, Moderate, []string{"sodium sulphite", "sodium sulfite"}},
+	{"E223", "Sodium metabisulphite", Preservative, Moderate, []string{"sodium metabisulphite", "sodium metabisulfite"}},
+	{"E224", "Potassium metabisulphite", Preservative, Moderate, []string{"potassium metabisulphite", "potassium metabisulfite"}},
+	{"E234", "Nisin", Preservative, None, []string{"nisin"}},
+	{"E235", "Natamycin", Preservative, Low, []string{"natamycin"}},
+	{"E249", "Potassium nitrite", Preservative, High, []string{"potassium nitrite"}},
+	{"E250", "Sodium nitrite", Preservative, High, []string{"sodium nitrite"}},
+	{"E251", "Sodium nitrate", Preservative, High, []string{"sodium nitrate"}},
+	{"E252", "Potassium nitrate", Preservative, High, []string{"potassium nitrate"}},
+	{"E260", "Acetic acid", AcidityRegulator, None, []string{"acetic acid"}},
+	{"E262", "Sodium acetates", Preservative, None, []string{"sodium acetate", "sodium diacetate"}},
+	{"E270", "Lactic acid", AcidityRegulator, None, []string{"lactic acid"}},
+	{"E280", "Propionic acid", Preservative, None, []string{"propionic acid"}},
+	{"E281", "Sodium propionate", Preservative, None, []string{"sodium propionate"}},
+	{"E282", "Calcium propionate", Preservative, Low, []string{"calcium propionate"}},
+	{"E290", "Carbon dioxide", Preservative, None, []string{"carbon dioxide"}},
+	{"E296", "Malic acid", AcidityRegulator, None, []string{"malic acid"}},
+
+	// Antioxidants and acidity regulators
+	{"E300", "Ascorbic acid", Antioxidant, None, []string{"ascorbic acid"}},
+	{"E301", "Sodium ascorbate", Antioxidant, None, []string{"sodium ascorbate"}},
+	{"E304", "Ascorbyl palmitate", Antioxidant, None, []string{"ascorbyl palmitate"}},
+	{"E306", "Tocopherols", Antioxidant, None, []string{"tocopherol", "mixed tocopherols"}},
+	{"E310", "Propyl gallate", Antioxidant, Moderate, []string{"propyl gallate"}},
+	{"E319", "TBHQ", Antioxidant, Moderate, []string{"tbhq", "tertiary butylhydroquinone", "tert-butylhydroquinone"}},
+	{"E320", "BHA", Antioxidant, High, []string{"bha", "butylated hydroxyanisole"}},
+	{"E321", "BHT", Antioxidant, Moderate, []string{"bht", "butylated hydroxytoluene"}},
+	{"E322", "Lecithins", Emulsifier, None, []string{"lecithin", "soy lecithin", "soya lecithin", "sunflower lecithin"}},
+	{"E325", "Sodium lactate", AcidityRegulator, None, []string{"sodium lactate"}},
+	{"E330", "Citric acid", AcidityRegulator, None, []string{"citric acid"}},
+	{"E331", "Sodium citrates", AcidityRegulator, None, []string{"sodium citrate", "trisodium citrate"}},
+	{"E332", "Potassium citrates", AcidityRegulator, None, []string{"potassium citrate"}},
+	{"E334", "Tartaric acid", AcidityRegulator, None, []string{"tartaric acid"}},
+	{"E338", "Phosphoric acid", AcidityRegulator, Low, []string{"phosphoric acid"}},
+	{"E339", "Sodium phosphates", AcidityRegulator, Low, []string{"sodium phosphate", "disodium phosphate", "trisodium phosphate"}},
+	{"E340", "Potassium phosphates", AcidityRegulator, Low, []string{"potassium phosphate", "dipotassium phosphate"}},
+	{"E341", "Calcium phosphates", AcidityRegulator, Low, []string{"calcium phosphate", "tricalcium phosphate"}},
+
+	// Thickeners, stabilisers and emulsifiers
+	{"E400", "Alginic acid", Thickener, None, []string{"alginic acid"}},
+	{"E401", "Sodium alginate", Thickener, None, []string{"sodium alginate"}},
+	{"E406", "Agar", Thickener, None, []string{"agar", "agar-agar"}},
+	{"E407", "Carrageenan", Thickener, Moderate, []string{"carrageenan"}},
+	{"E410", "Locust bean gum", Thickener, None, []string{"locust bean gum", "carob bean gum"}},
+	{"E412", "Guar gum", Thickener, None, []string{"guar gum"}},
+	{"E414", "Gum arabic", Stabilizer, None, []string{"gum arabic", "acacia gum"}},
+	{"E415", "Xanthan gum", Thickener, None, []string{"xanthan gum"}},
+	{"E418", "Gellan gum", Thickener, None, []string{"gellan gum"}},
+	{"E420", "Sorbitol", Sweetener, Low, []string{"sorbitol"}},
+	{"E421", "Mannitol", Sweetener, Low, []string{"mannitol"}},
+	{"E422", "Glycerol", Humectant, None, []string{"glycerol", "glycerin", "glycerine"}},
+	{"E433", "Polysorbate 80", Emulsifier, Moderate, []string{"polysorbate 80"}},
+	{"E435", "Polysorbate 60", Emulsifier, Moderate, []string{"polysorbate 60"}},
+	{"E440", "Pectins", Thickener, None, []string{"pectin"}},
+	{"E450", "Diphosphates", RaisingAgent, Low, []string{"sodium acid pyrophosphate", "disodium diphosphate", "sodium pyrophosphate"}},
+	{"E451", "Triphosphates", Stabilizer, Low, []string{"sodium tripolyphosphate", "pentasodium triphosphate"}},
+	{"E452", "Polyphosphates", Stabilizer, Low, []string{"sodium hexametaphosphate", "sodium polyphosphate"}},
+	{"E460", "Cellulose", Thickener, None, []string{"microcrystalline cellulose", "powdered cellulose", "cellulose gel"}},
+	{"E461", "Methyl cellulose", Thickener, None, []string{"methylcellulose", "methyl cellulose"}},
+	{"E464", "Hydroxypropyl methyl cellulose", Thickener, None, []string{"hydroxypropyl methylcellulose", "hpmc"}},
+	{"E466", "Carboxymethyl cellulose", Thickener, Moderate, []string{"carboxymethylcellulose", "carboxymethyl cellulose", "cellulose gum", "sodium carboxymethylcellulose"}},
+	{"E471", "Mono- and diglycerides of fatty acids", Emulsifier, Low, []string{"mono- and diglycerides", "mono and diglycerides", "monoglycerides", "diglycerides"}},
+	{"E472e", "DATEM", Emulsifier, Low, []string{"datem", "diacetyl tartaric acid esters of mono- and diglycerides"}},
+	{"E475", "Polyglycerol esters of fatty acids", Emulsifier, Low, []string{"polyglycerol esters of fatty acids"}},
+	{"E476", "Polyglycerol polyricinoleate", Emulsifier, Low, []string{"polyglycerol polyricinoleate", "pgpr"}},
+	{"E481", "Sodium stearoyl lactylate", Emulsifier, Low, []string{"sodium stearoyl lactylate", "sodium stearoyl-2-lactylate"}},
+	{"E482", "Calcium stearoyl lactylate", Emulsifier, Low, []string{"calcium stearoyl lactylate", "calcium stearoyl-2-lactylate"}},
+
+	// Acidity regulators, raising and anti-caking agents
+	{"E500", "Sodium carbonates", RaisingAgent, None, []string{"sodium bicarbonate", "sodium hydrogen carbonate", "baking soda", "sodium carbonate"}},
+	{"E501", "Potassium carbonates", AcidityRegulator, None, []string{"potassium carbonate"}},
+	{"E503", "Ammonium carbonates", RaisingAgent, None, []string{"ammonium bicarbonate", "ammonium carbonate"}},
+	{"E504", "Magnesium carbonates", AntiCaking, None, []string{"magnesium carbonate"}},
+	{"E509", "Calcium chloride", Stabilizer, None, []string{"calcium chloride"}},
+	{"E524", "Sodium hydroxide", AcidityRegulator, None, []string{"sodium hydroxide"}},
+	{"E551", "Silicon dioxide", AntiCaking, Low, []string{"silicon dioxide", "silica"}},
+	{"E552", "Calcium silicate", AntiCaking, None, []string{"calcium silicate"}},
+	{"E570", "Fatty acids", AntiCaking, None, []string{"stearic acid"}},
+	{"E575", "Glucono-delta-lactone", AcidityRegulator, None, []string{"glucono delta lactone", "glucono-delta-lactone"}},
+
+	// Flavour enhancers
+	{"E620", "Glutamic acid", FlavorEnhancer, Low, []string{"glutamic acid"}},
+	{"E621", "Monosodium glutamate", FlavorEnhancer, Low, []string{"monosodium glutamate", "msg"}},
+	{"E627", "Disodium guanylate", FlavorEnhancer, Low, []string{"disodium guanylate"}},
+	{"E631", "Disodium inosinate", FlavorEnhancer, Low, []string{"disodium inosinate"}},
+	{"E635", "Disodium 5'-ribonucleotides", FlavorEnhancer, Low, []string{"disodium 5'-ribonucleotides", "disodium ribonucleotides"}},
+
+	// Glazing agents
+	{"E901", "Beeswax", GlazingAgent, None, []string{"beeswax"}},
+	{"E903", "Carnauba wax", GlazingAgent, None, []string{"carnauba wax"}},
+	{"E904", "Shellac", GlazingAgent, None, []string{"shellac"}},
+
+	// Sweeteners
+	{"E950", "Acesulfame K", Sweetener, Moderate, []string{"acesulfame k", "acesulfame potassium", "acesulfame-k"}},
+	{"E951", "Aspartame", Sweetener, High, []string{"aspartame"}},
+	{"E952", "Cyclamates", Sweetener, Moderate, []string{"cyclamate", "sodium cyclamate"}},
+	{"E954", "Saccharin", Sweetener, Moderate, []string{"saccharin", "sodium saccharin"}},
+	{"E955", "Sucralose", Sweetener, Moderate, []string{"sucralose"}},
+	{"E957", "Thaumatin", Sweetener, Low, []string{"thaumatin"}},
+	{"E959", "Neohesperidin DC", Sweetener, Low, []string{"neohesperidin dc", "neohesperidine dc", "neohesperidin dihydrochalcone"}},
+	{"E960", "Steviol glycosides", Sweetener, Low, []string{"steviol glycosides", "stevia extract", "stevia", "rebaudioside a"}},
+	{"E961", "Neotame", Sweetener, Low, []string{"neotame"}},
+	{"E962", "Aspartame-acesulfame salt", Sweetener, High, []string{"aspartame-acesulfame salt"}},
+	{"E965", "Maltitol", Sweetener, Low, []string{"maltitol"}},
+	{"E967", "Xylitol", Sweetener, Low, []string{"xylitol"}},
+	{"E968", "Erythritol", Sweetener, Low, []string{"erythritol"}},
+	{"E969", "Advantame", Sweetener, Low, []string{"advantame"}},
+
+	// Modified starches
+	{"E1404", "Oxidised starch", Thickener, None, nil},
+	{"E1414", "Acetylated distarch phosphate", Thickener, None, nil},
+	{"E1420", "Acetylated starch", Thickener, None, nil},
+	{"E1422", "Acetylated distarch adipate", Thickener, None, nil},
+	{"E1442", "Hydroxypropyl distarch phosphate", Thickener, None, nil},
+	{"E1450", "Starch sodium octenyl succinate", Emulsifier, None, nil},
+}
@@ -0,0 +1,202 @@
+package allergens
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Regulations an allergen is declared under.
+const (
+	FDA = "fda" // US FDA major food allergens (FALCPA + FASTER Act)
+	EU  = "eu"  // EU Regulation 1169/2011 Annex II
+)
+
+// Statuses of a detected allergen.
+const (
+	Contains   = "contains"
+	MayContain = "may-contain"
+)
+
+// Allergen is a major allergen with the ingredient terms that reveal it.
+type Allergen struct {
+	ID          string
+	Name        string
+	Regulations []string
+	// Terms are lowercase ingredient names and derived ingredients.
+	Terms []string
+	// Exclusions are phrases whose terms do not count, e.g. "cocoa butter"
+	// for milk.
+	Exclusions []string
+
+	pattern   *regexp.Regexp
+	exclusion *regexp.Regexp
+}
+
+// Match is an ingredient span that revealed an allergen. Start and End are
+// character (rune) offsets into the ingredient text.
+type Match struct {
+	Term  string `json:"term"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	// Precautionary marks a match inside a "may contain" statement.
+	Precautionary bool `json:"precautionary"`
+}
+
+// Detection is an allergen found in an ingredient list. Status is Contains
+// when any match is an ingredient, MayContain when all are precautionary.
+type Detection struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Regulations []string `json:"regulations"`
+	Status      string   `json:"status"`
+	Matches     []Match  `json:"matches"`
+}
+
+func init() {
+	for i := range allergens {
+		a := &allergens[i]
+		a.pattern = wordPattern(a.Terms)
+		if len(a.Exclusions) > 0 {
+			a.exclusion = wordPattern(a.Exclusions)
+		}
+	}
+}
+
+// wordPattern matches any of the terms as whole words, optionally plural.
+func wordPattern(terms []string) *regexp.Regexp {
+	sorted := append([]string(nil), terms...)
+	// longest first, so "wheat flour" wins over "wheat"
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, len(sorted))
+	for i, t := range sorted {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)(?:e?s)?\b`)
+}
+
+// precautionary finds the start of "may contain" style statements; each
+// runs to the end of its sentence.
+var precautionary = regexp.MustCompile(`\b(?:may (?:also )?contain|may be present|traces? of|produced in a (?:facility|factory|plant)|manufactured (?:in|on)|made (?:in|on) (?:a )?(?:facility|factory|equipment|shared)|processed (?:in|on) (?:a )?(?:facility|equipment|shared)|puede contener|peut contenir|trazas de|traces de)\b`)
+
+// CheckRegulations reports an error for unknown regulation names.
+func CheckRegulations(regulations []string) error {
+	for _, r := range regulations {
+		if r != FDA && r != EU {
+			return fmt.Errorf("unknown allergen regulation %q (available: %s, %s)", r, FDA, EU)
+		}
+	}
+	return nil
+}
+
+// CheckIDs reports an error for unknown allergen IDs.
+func CheckIDs(ids []string) error {
+	for _, id := range ids {
+		found := false
+		for _, a := range allergens {
+			found = found || a.ID == id
+		}
+		if !found {
+			known := make([]string, len(allergens))
+			for i, a := range allergens {
+				known[i] = a.ID
+			}
+			return fmt.Errorf("unknown allergen %q (available: %s)", id, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// Detect finds the allergens in an ingredient statement. Only allergens
+// declared under one of the given regulations are reported; all are when
+// regulations is empty.
+func Detect(ingredients string, regulations ...string) []Detection {
+	text := lower(ingredients)
+	precautions := precautionarySpans(text)
+
+	detections := []Detection{}
+	for i := range allergens {
+		a := &allergens[i]
+		if !a.declaredUnder(regulations) {
+			continue
+		}
+		var excluded [][]int
+		if a.exclusion != nil {
+			excluded = a.exclusion.FindAllStringIndex(text, -1)
+		}
+
+		d := Detection{ID: a.ID, Name: a.Name, Regulations: a.Regulations, Status: MayContain}
+		for _, loc := range a.pattern.FindAllStringIndex(text, -1) {
+			if within(loc, excluded) {
+				continue
+			}
+			m := Match{
+				Term:          strings.TrimSpace(text[loc[0]:loc[1]]),
+				Text:          ingredients[loc[0]:loc[1]],
+				Start:         utf8.RuneCountInString(text[:loc[0]]),
+				End:           utf8.RuneCountInString(text[:loc[1]]),
+				Precautionary: within(loc, precautions),
+			}
+			if !m.Precautionary {
+				d.Status = Contains
+			}
+			d.Matches = append(d.Matches, m)
+		}
+		if len(d.Matches) > 0 {
+			detections = append(detections, d)
+		}
+	}
+	return detections
+}
+
+func (a *Allergen) declaredUnder(regulations []string) bool {
+	if len(regulations) == 0 {
+		return true
+	}
+	for _, want := range regulations {
+		for _, r := range a.Regulations {
+			if r == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lower lowercases rune by rune, keeping byte offsets aligned with the
+// original for the ASCII and Latin text ingredient lists are written in.
+func lower(s string) string {
+	return strings.Map(func(r rune) rune {
+		l := unicode.ToLower(r)
+		if utf8.RuneLen(l) != utf8.RuneLen(r) {
+			return r
+		}
+		return l
+	}, s)
+}
+
+func precautionarySpans(text string) [][]int {
+	var spans [][]int
+	for _, loc := range precautionary.FindAllStringIndex(text, -1) {
+		end := len(text)
+		if i := strings.IndexAny(text[loc[1]:], ".\n"); i >= 0 {
+			end = loc[1] + i
+		}
+		spans = append(spans, []int{loc[0], end})
+	}
+	return spans
+}
+
+// within reports whether loc lies inside any of the spans.
+func within(loc []int, spans [][]int) bool {
+	for _, s := range spans {
+		if loc[0] >= s[0] && loc[1] <= s[1] {
+			return true
+		}
+	}
+	return false
+}
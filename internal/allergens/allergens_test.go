@@ -0,0 +1,98 @@
+package allergens
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// summary renders detections as "id:status" pairs.
+func summary(detections []Detection) string {
+	var out []string
+	for _, d := range detections {
+		out = append(out, d.ID+":"+d.Status)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		ingredients string
+		regulations []string
+		want        string
+	}{
+		{"Enriched wheat flour, sugar, butter, eggs, salt", nil,
+			"milk:contains egg:contains wheat:contains gluten:contains"},
+		{"Enriched wheat flour, sugar, butter, eggs, salt", []string{FDA},
+			"milk:contains egg:contains wheat:contains"},
+		// exclusions: cocoa butter is not milk, eggplant is not egg,
+		// nutmeg and coconut are not tree nuts, buckwheat is not wheat
+		{"Cocoa butter, eggplant, nutmeg, coconut milk, buckwheat flour", nil, ""},
+		{"Peanuts, salt. May contain traces of almonds and sesame.", nil,
+			"tree-nuts:may-contain peanuts:contains sesame:may-contain"},
+		// an allergen both listed and in a warning is contained
+		{"Milk chocolate (sugar, whole milk powder). Produced in a facility that also processes milk.", nil,
+			"milk:contains"},
+		{"Shrimp, squid, soy sauce (water, soybeans, wheat), sulphites", []string{EU},
+			"crustaceans:contains molluscs:contains wheat:contains gluten:contains soy:contains sulphites:contains"},
+		{"Harina de trigo, huevo, leche", nil, "milk:contains egg:contains wheat:contains gluten:contains"},
+		{"", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := summary(Detect(tt.ingredients, tt.regulations...)); got != tt.want {
+			t.Errorf("Detect(%q, %v) = %q, want %q", tt.ingredients, tt.regulations, got, tt.want)
+		}
+	}
+}
+
+func TestDetectMatches(t *testing.T) {
+	text := "Água, LECHE en polvo. Puede contener cacahuetes."
+	got := Detect(text)
+	if summary(got) != "milk:contains peanuts:may-contain" {
+		t.Fatalf("Detect(%q) = %q", text, summary(got))
+	}
+	runes := []rune(text)
+	for _, d := range got {
+		for _, m := range d.Matches {
+			if string(runes[m.Start:m.End]) != m.Text {
+				t.Errorf("%s match %+v does not index %q by rune", d.ID, m, text)
+			}
+		}
+	}
+	if m := got[0].Matches[0]; m.Text != "LECHE" || m.Term != "leche" || m.Precautionary {
+		t.Errorf("milk match = %+v", m)
+	}
+	if m := got[1].Matches[0]; !m.Precautionary {
+		t.Errorf("peanut match %+v is not precautionary", m)
+	}
+}
+
+func TestChecks(t *testing.T) {
+	if err := CheckRegulations([]string{FDA, EU}); err != nil {
+		t.Error(err)
+	}
+	if err := CheckRegulations([]string{"uk"}); err == nil {
+		t.Error("unknown regulation accepted")
+	}
+	if err := CheckIDs([]string{"milk", "tree-nuts"}); err != nil {
+		t.Error(err)
+	}
+	if err := CheckIDs([]string{"milk", "nightshade"}); err == nil {
+		t.Error("unknown allergen accepted")
+	}
+}
+
+func TestTermsAreCovered(t *testing.T) {
+	for _, a := range allergens {
+		for _, term := range a.Terms {
+			text := fmt.Sprintf("Water, %s, salt", term)
+			found := false
+			for _, d := range Detect(text) {
+				found = found || d.ID == a.ID
+			}
+			if !found {
+				t.Errorf("%s term %q is not detected", a.ID, term)
+			}
+		}
+	}
+}
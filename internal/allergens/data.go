@@ -0,0 +1,182 @@
+package allergens
+
+// allergens covers the FDA major food allergens and the EU 14. Terms
+// include derived ingredients and a few common non-English names; they are
+// ASCII so word boundaries match reliably.
+var allergens = []Allergen{
+	{
+		ID:          "milk",
+		Name:        "Milk",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"milk", "milk solids", "milk powder", "skimmed milk", "buttermilk", "butter", "butterfat",
+			"butter oil", "cream", "sour cream", "cheese", "curd", "ghee", "yogurt", "yoghurt",
+			"kefir", "whey", "whey protein", "casein", "caseinate", "sodium caseinate",
+			"calcium caseinate", "lactose", "lactalbumin", "lactoglobulin", "lactoferrin",
+			"milk fat", "dairy", "paneer", "custard", "nougat", "recaldent",
+			"leche", "lait", "gatas", "queso", "fromage", "kesong puti",
+		},
+		Exclusions: []string{
+			"cocoa butter", "cacao butter", "shea butter", "peanut butter", "nut butter",
+			"almond butter", "apple butter", "coconut milk", "coconut cream", "almond milk",
+			"soy milk", "soya milk", "oat milk", "rice milk", "cashew milk", "cream of tartar",
+			"cream of coconut", "milk thistle", "lactose free", "lactose-free",
+			"non-dairy", "dairy free", "dairy-free", "bean curd", "leche de coco", "lait de coco",
+		},
+	},
+	{
+		ID:          "egg",
+		Name:        "Egg",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"egg", "egg white", "egg yolk", "whole egg", "dried egg", "egg powder", "albumin",
+			"albumen", "ovalbumin", "ovomucoid", "ovomucin", "ovoglobulin", "ovovitellin",
+			"livetin", "lysozyme", "mayonnaise", "meringue", "eggnog",
+			"huevo", "oeuf", "itlog",
+		},
+		Exclusions: []string{"eggplant", "egg-free", "egg free"},
+	},
+	{
+		ID:          "fish",
+		Name:        "Fish",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"fish", "fish sauce", "fish oil", "fish gelatin", "anchovy", "anchovies", "bass",
+			"cod", "haddock", "hake", "halibut", "herring", "mackerel", "pollock", "salmon",
+			"sardine", "snapper", "sole", "swordfish", "tilapia", "trout", "tuna", "bangus",
+			"milkfish", "dilis", "tinapa", "bagoong isda", "patis", "surimi", "worcestershire",
+			"pescado", "poisson",
+		},
+		Exclusions: []string{"shellfish", "fish-free"},
+	},
+	{
+		ID:          "crustaceans",
+		Name:        "Crustacean shellfish",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"crustacean", "crab", "crayfish", "crawfish", "lobster", "prawn", "shrimp",
+			"krill", "langoustine", "scampi", "shrimp paste", "bagoong alamang", "alamang",
+			"hipon", "camaron", "camarones",
+		},
+	},
+	{
+		ID:          "molluscs",
+		Name:        "Molluscs",
+		Regulations: []string{EU},
+		Terms: []string{
+			"mollusc", "mollusk", "clam", "mussel", "oyster", "oyster sauce", "scallop",
+			"squid", "calamari", "octopus", "cuttlefish", "snail", "escargot", "abalone",
+			"whelk", "periwinkle", "tahong", "talaba", "pusit",
+		},
+	},
+	{
+		ID:          "tree-nuts",
+		Name:        "Tree nuts",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"tree nut", "nut", "almond", "brazil nut", "cashew", "hazelnut", "filbert",
+			"macadamia", "pecan", "pistachio", "walnut", "queensland nut", "praline",
+			"marzipan", "gianduja", "nougat", "pili", "pili nut", "nut oil",
+		},
+		Exclusions: []string{
+			"nutmeg", "coconut", "butternut", "doughnut", "donut", "water chestnut",
+			"nutrition", "nutritional", "nutrient", "peanut", "ground nut", "groundnut",
+			"nut-free", "nut free",
+		},
+	},
+	{
+		ID:          "peanuts",
+		Name:        "Peanuts",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"peanut", "peanut butter", "peanut oil", "peanut flour", "groundnut", "ground nut",
+			"arachis", "arachis oil", "monkey nut", "mani", "cacahuete", "cacahuate",
+		},
+		Exclusions: []string{"peanut-free", "peanut free"},
+	},
+	{
+		ID:          "wheat",
+		Name:        "Wheat",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"wheat", "wheat flour", "whole wheat", "flour", "enriched flour", "bread crumbs",
+			"breadcrumbs", "bulgur", "couscous", "durum", "semolina", "spelt", "farro",
+			"einkorn", "emmer", "kamut", "seitan", "wheat starch", "wheat gluten",
+			"wheat germ", "bran", "farina", "graham", "triticale", "trigo",
+		},
+		Exclusions: []string{
+			"buckwheat", "buckwheat flour", "rice flour", "corn flour", "cornflour", "potato flour",
+			"tapioca flour", "cassava flour", "almond flour", "coconut flour", "soy flour",
+			"soya flour", "chickpea flour", "sorghum flour", "millet flour", "quinoa flour",
+			"teff flour", "oat flour", "rye flour", "barley flour",
+			"peanut flour", "rice bran", "oat bran", "wheat-free", "wheat free",
+		},
+	},
+	{
+		ID:          "gluten",
+		Name:        "Cereals containing gluten",
+		Regulations: []string{EU},
+		Terms: []string{
+			"gluten", "wheat", "wheat flour", "whole wheat", "flour", "barley", "barley malt",
+			"malt", "malt extract", "malt vinegar", "rye", "oat", "oats", "oatmeal", "spelt",
+			"kamut", "triticale", "durum", "semolina", "bulgur", "couscous", "farro",
+			"seitan", "brewer's yeast", "trigo", "cebada", "centeno", "avena",
+		},
+		Exclusions: []string{
+			"buckwheat", "buckwheat flour", "rice flour", "corn flour", "cornflour", "potato flour",
+			"tapioca flour", "cassava flour", "almond flour", "coconut flour", "soy flour",
+			"soya flour", "chickpea flour", "sorghum flour", "millet flour", "quinoa flour",
+			"teff flour", "peanut flour", "gluten-free", "gluten free",
+		},
+	},
+	{
+		ID:          "soy",
+		Name:        "Soy",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"soy", "soya", "soybean", "soy lecithin", "soya lecithin", "soy sauce",
+			"soy protein", "soy flour", "edamame", "miso", "natto", "tempeh", "tofu",
+			"tamari", "shoyu", "textured vegetable protein", "tvp", "toyo", "tokwa", "taho",
+			"soja",
+		},
+		Exclusions: []string{"soy-free", "soy free"},
+	},
+	{
+		ID:          "sesame",
+		Name:        "Sesame",
+		Regulations: []string{FDA, EU},
+		Terms: []string{
+			"sesame", "sesame seed", "sesame oil", "sesame paste", "tahini", "tahina",
+			"halva", "halvah", "gomasio", "benne", "gingelly", "ajonjoli",
+		},
+	},
+	{
+		ID:          "celery",
+		Name:        "Celery",
+		Regulations: []string{EU},
+		Terms:       []string{"celery", "celeriac", "celery seed", "celery salt", "apio", "celeri"},
+	},
+	{
+		ID:          "mustard",
+		Name:        "Mustard",
+		Regulations: []string{EU},
+		Terms:       []string{"mustard", "mustard seed", "mustard flour", "mustard oil", "mostaza", "moutarde"},
+	},
+	{
+		ID:          "sulphites",
+		Name:        "Sulphur dioxide and sulphites",
+		Regulations: []string{EU},
+		Terms: []string{
+			"sulphite", "sulfite", "sulphur dioxide", "sulfur dioxide", "metabisulphite",
+			"metabisulfite", "bisulphite", "bisulfite", "e220", "e221", "e222", "e223",
+			"e224", "e225", "e226", "e227", "e228", "e 220", "e 221", "e 222", "e 223",
+			"e 224", "e 225", "e 226", "e 227", "e 228",
+		},
+	},
+	{
+		ID:          "lupin",
+		Name:        "Lupin",
+		Regulations: []string{EU},
+		Terms:       []string{"lupin", "lupine", "lupini", "lupin flour", "altramuz", "altramuces"},
+	},
+}
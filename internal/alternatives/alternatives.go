@@ -0,0 +1,266 @@
+package alternatives
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Sush1sui/internal/nutrients"
+)
+
+// MinSimilarity is the share of name words a product outside the target's
+// category must have in common with it to be suggested; see overlap.
+const MinSimilarity = 0.3
+
+// minGain is the least score improvement worth suggesting.
+const minGain = 0.01
+
+// Weight is one term of the score: Weight times the amount per 100 g as a
+// share of Reference, in the nutrient's canonical unit. Negative weights
+// penalize a nutrient.
+type Weight struct {
+	Nutrient  string  `json:"nutrient"`
+	Weight    float64 `json:"weight"`
+	Reference float64 `json:"reference"`
+}
+
+var (
+	mu sync.RWMutex
+	// weights score sugars, sodium and saturated fat against their daily
+	// values, with energy density and protein counting for half.
+	weights = []Weight{
+		{"sugars", -1, 50},
+		{"sodium", -1, 2300},
+		{"saturated-fat", -1, 20},
+		{"fiber", 1, 28},
+		{"energy", -0.5, 2000},
+		{"protein", 0.5, 50},
+	}
+)
+
+// LoadFile replaces the score with a JSON array of {"nutrient", "weight",
+// "reference"}.
+func LoadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded []Weight
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(loaded) == 0 {
+		return fmt.Errorf("%s has no score weights", path)
+	}
+	for _, w := range loaded {
+		if _, ok := nutrients.ByID(w.Nutrient); !ok {
+			return fmt.Errorf("unknown nutrient %q in score weights", w.Nutrient)
+		}
+		if w.Reference <= 0 {
+			return fmt.Errorf("score weight for %s needs a positive reference", w.Nutrient)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	weights = loaded
+	return nil
+}
+
+// Candidate is a product that may be suggested, or the product suggestions
+// are made for. Amounts are per 100 g by nutrient registry ID; see
+// nutrients.Amounts.
+type Candidate struct {
+	ID       string
+	Name     string
+	Brand    string
+	Source   string
+	Category string
+	Amounts  map[string]float64
+}
+
+// Suggestion is a healthier alternative. Gain is how much higher its score
+// is than the scanned product's; Amounts are the scored nutrients per 100 g.
+type Suggestion struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Brand        string             `json:"brand"`
+	Source       string             `json:"source"`
+	Category     string             `json:"category,omitempty"`
+	Score        float64            `json:"score"`
+	Gain         float64            `json:"gain"`
+	SameCategory bool               `json:"sameCategory"`
+	Similarity   float64            `json:"similarity"`
+	Amounts      map[string]float64 `json:"amounts"`
+	Improvements []string           `json:"improvements"`
+}
+
+// Score rates amounts per 100 g, higher being healthier, or returns false
+// when none of the scored nutrients is reported.
+func Score(amounts map[string]float64) (float64, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return score(amounts)
+}
+
+func score(amounts map[string]float64) (float64, bool) {
+	total, found := 0.0, false
+	for _, w := range weights {
+		if v, ok := amounts[w.Nutrient]; ok {
+			total += w.Weight * v / w.Reference
+			found = true
+		}
+	}
+	return round(total, 3), found
+}
+
+// Suggest returns up to limit candidates that are like the target and
+// score higher, best first. A candidate is like the target when it shares
+// its category or enough of its name, and is scored only when it reports
+// every scored nutrient the target does.
+func Suggest(target Candidate, candidates []Candidate, limit int) []Suggestion {
+	mu.RLock()
+	defer mu.RUnlock()
+	suggestions := []Suggestion{}
+	base, ok := score(target.Amounts)
+	if !ok {
+		return suggestions
+	}
+	targetWords := words(target.Name)
+	for _, c := range candidates {
+		if c.ID == target.ID || !comparable(target.Amounts, c.Amounts) {
+			continue
+		}
+		sameCategory := target.Category != "" && strings.EqualFold(target.Category, c.Category)
+		similarity := overlap(targetWords, words(c.Name))
+		if !sameCategory && similarity < MinSimilarity {
+			continue
+		}
+		s, _ := score(c.Amounts)
+		if s-base < minGain {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{
+			ID:           c.ID,
+			Name:         c.Name,
+			Brand:        c.Brand,
+			Source:       c.Source,
+			Category:     c.Category,
+			Score:        s,
+			Gain:         round(s-base, 3),
+			SameCategory: sameCategory,
+			Similarity:   round(similarity, 2),
+			Amounts:      scored(c.Amounts),
+			Improvements: improvements(target.Amounts, c.Amounts),
+		})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Similarity > suggestions[j].Similarity
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// comparable reports whether the candidate has every scored nutrient the
+// target has, so an unreported nutrient cannot pass for a low one.
+func comparable(target, candidate map[string]float64) bool {
+	for _, w := range weights {
+		if _, ok := target[w.Nutrient]; !ok {
+			continue
+		}
+		if _, ok := candidate[w.Nutrient]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func scored(amounts map[string]float64) map[string]float64 {
+	out := map[string]float64{}
+	for _, w := range weights {
+		if v, ok := amounts[w.Nutrient]; ok {
+			out[w.Nutrient] = round(v, 2)
+		}
+	}
+	return out
+}
+
+// improvements describe the scored nutrients the candidate is at least 10%
+// better in, e.g. "45% less sugar" or "3 g more dietary fiber".
+func improvements(target, candidate map[string]float64) []string {
+	out := []string{}
+	for _, w := range weights {
+		before, ok1 := target[w.Nutrient]
+		after, ok2 := candidate[w.Nutrient]
+		if !ok1 || !ok2 || w.Weight == 0 {
+			continue
+		}
+		name, unit := w.Nutrient, ""
+		if d, ok := nutrients.ByID(w.Nutrient); ok {
+			name, unit = strings.ToLower(d.Name(nutrients.DefaultLocale)), d.Unit
+		}
+		switch {
+		case w.Weight < 0 && before > 0 && after <= before*0.9:
+			out = append(out, fmt.Sprintf("%.0f%% less %s", (before-after)/before*100, name))
+		case w.Weight > 0 && before > 0 && after >= before*1.1:
+			out = append(out, fmt.Sprintf("%.0f%% more %s", (after-before)/before*100, name))
+		case w.Weight > 0 && before == 0 && after > 0:
+			out = append(out, fmt.Sprintf("%g %s more %s", round(after, 1), unit, name))
+		}
+	}
+	return out
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\d]+`)
+
+// stopWords carry no meaning about what a food is.
+var stopWords = map[string]bool{
+	"and": true, "with": true, "the": true, "for": true, "from": true, "of": true, "in": true,
+	"style": true, "flavor": true, "flavored": true, "flavour": true, "flavoured": true,
+	"original": true, "classic": true, "new": true, "brand": true,
+}
+
+// words are the distinct meaningful lowercase words of a name, singular.
+func words(name string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range wordPattern.FindAllString(strings.ToLower(name), -1) {
+		if len(w) < 3 || stopWords[w] {
+			continue
+		}
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		set[w] = true
+	}
+	return set
+}
+
+// overlap is the share of the words of both names that they have in
+// common.
+func overlap(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,99 @@
+package alternatives
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	s, ok := Score(map[string]float64{"sugars": 25, "sodium": 460, "fiber": 7, "vitamin-c": 90})
+	if !ok || s != -0.45 {
+		t.Errorf("Score = %v, %v; want -0.45", s, ok)
+	}
+	if _, ok := Score(map[string]float64{"vitamin-c": 90}); ok {
+		t.Error("Score without scored nutrients reported a score")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	target := Candidate{ID: "t", Name: "Chocolate Chip Cookies", Category: "en:biscuits",
+		Amounts: map[string]float64{"sugars": 30, "fiber": 0}}
+	candidates := []Candidate{
+		target,
+		{ID: "oat", Name: "Oat biscuits", Category: "en:biscuits", Amounts: map[string]float64{"sugars": 15, "fiber": 5}},
+		{ID: "lite", Name: "Chocolate chip cookies, reduced sugar", Amounts: map[string]float64{"sugars": 20, "fiber": 0}},
+		{ID: "apple", Name: "Apples", Amounts: map[string]float64{"sugars": 10, "fiber": 2.4}},
+		{ID: "worse", Name: "Chocolate chip cookies, double", Category: "en:biscuits", Amounts: map[string]float64{"sugars": 40, "fiber": 0}},
+		{ID: "unreported", Name: "Chocolate chip cookies, thin", Category: "en:biscuits", Amounts: map[string]float64{"sugars": 1}},
+	}
+	got := Suggest(target, candidates, 5)
+	var ids []string
+	for _, s := range got {
+		ids = append(ids, s.ID)
+	}
+	if strings.Join(ids, " ") != "oat lite" {
+		t.Fatalf("suggestions = %v, want oat and lite", ids)
+	}
+
+	oat := got[0]
+	if !oat.SameCategory || oat.Gain != 0.479 || oat.Score != -0.121 {
+		t.Errorf("oat = %+v", oat)
+	}
+	if want := "50% less sugar; 5 g more dietary fiber"; strings.Join(oat.Improvements, "; ") != want {
+		t.Errorf("improvements = %q, want %q", oat.Improvements, want)
+	}
+	if lite := got[1]; lite.SameCategory || lite.Similarity != 0.6 {
+		t.Errorf("lite = %+v, want a name match", lite)
+	}
+	if got := Suggest(target, candidates, 1); len(got) != 1 {
+		t.Errorf("limit 1 gave %d suggestions", len(got))
+	}
+	if got := Suggest(Candidate{Name: "Water"}, candidates, 5); len(got) != 0 {
+		t.Errorf("unscored target got %d suggestions", len(got))
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := words("The Original Crackers & Cheese, 3 pcs")
+	for _, w := range []string{"cracker", "cheese", "pcs"} {
+		if !got[w] {
+			t.Errorf("words lack %q: %v", w, got)
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("words = %v", got)
+	}
+	if o := overlap(words("glass noodles"), words("Glass Noodle Soup")); o != 2.0/3 {
+		t.Errorf("overlap = %v, want 2/3", o)
+	}
+	if o := overlap(words(""), words("soup")); o != 0 {
+		t.Errorf("overlap with an empty name = %v", o)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	saved := weights
+	defer func() { weights = saved }()
+
+	path := filepath.Join(t.TempDir(), "weights.json")
+	os.WriteFile(path, []byte(`[{"nutrient": "sodium", "weight": -2, "reference": 1000}]`), 0o644)
+	if err := LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := Score(map[string]float64{"sodium": 250, "sugars": 50}); !ok || s != -0.5 {
+		t.Errorf("Score with loaded weights = %v, %v; want -0.5", s, ok)
+	}
+
+	for _, bad := range []string{
+		`[]`,
+		`[{"nutrient": "unobtainium", "weight": 1, "reference": 1}]`,
+		`[{"nutrient": "sodium", "weight": 1, "reference": 0}]`,
+	} {
+		os.WriteFile(path, []byte(bad), 0o644)
+		if err := LoadFile(path); err == nil {
+			t.Errorf("weights %s accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Cache is a concurrency-safe in-memory map whose entries expire after a TTL.
+type Cache[V any] struct {
+	mu    sync.RWMutex
+	ttl   time.Duration
+	items map[string]entry[V]
+}
+
+func New[V any](ttl time.Duration) *Cache[V] {
+	return &Cache[V]{ttl: ttl, items: map[string]entry[V]{}}
+}
+
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.RLock()
+	e, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = entry[V]{value: value, expires: time.Now().Add(c.ttl)}
+}
+
+// Values returns every unexpired value and drops the expired ones.
+func (c *Cache[V]) Values() []V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	values := make([]V, 0, len(c.items))
+	for k, e := range c.items {
+		if now.After(e.expires) {
+			delete(c.items, k)
+			continue
+		}
+		values = append(values, e.value)
+	}
+	return values
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	c := New[int](time.Hour)
+	if _, ok := c.Get("a"); ok {
+		t.Error("empty cache has a")
+	}
+	c.Set("a", 1)
+	c.Set("a", 2)
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Errorf("Get(a) = %v, %v; want 2, true", v, ok)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	c := New[string](-time.Second)
+	c.Set("a", "stale")
+	if _, ok := c.Get("a"); ok {
+		t.Error("expired entry returned")
+	}
+}
+
+func TestCacheValues(t *testing.T) {
+	c := New[int](time.Hour)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if v := c.Values(); len(v) != 2 || v[0]+v[1] != 3 {
+		t.Errorf("Values() = %v, want 1 and 2", v)
+	}
+
+	stale := New[int](-time.Second)
+	stale.Set("a", 1)
+	if v := stale.Values(); len(v) != 0 || len(stale.items) != 0 {
+		t.Errorf("Values() = %v with %d entries kept, want expired entries dropped", v, len(stale.items))
+	}
+}
@@ -0,0 +1,49 @@
+package common
+
+// Nutrient bases: the amount of food a nutrient value refers to.
+const (
+	Per100g    = "100g"
+	PerServing = "serving"
+)
+
+// WithBasis copies nutrition with each nutrient tagged by its basis.
+func WithBasis(nutrition []map[string]any, basis string) []map[string]any {
+	return ScaleNutrition(nutrition, 1, basis)
+}
+
+// ScaleNutrition copies nutrition with every amount multiplied by factor and
+// tagged with basis. The input is left untouched since it may be cached.
+func ScaleNutrition(nutrition []map[string]any, factor float64, basis string) []map[string]any {
+	out := make([]map[string]any, 0, len(nutrition))
+	for _, n := range nutrition {
+		c := make(map[string]any, len(n)+1)
+		for k, v := range n {
+			c[k] = v
+		}
+		if amount, ok := n["amount"].(float64); ok {
+			c["amount"] = RoundTo(amount*factor, 2)
+		}
+		c["basis"] = basis
+		out = append(out, c)
+	}
+	return out
+}
+
+// DualBasis derives per-100g and per-serving nutrition from values given on
+// one basis and the serving weight in grams. Without a serving weight only
+// the basis the values came in is known; the other is nil.
+func DualBasis(nutrition []map[string]any, basis string, servingGrams float64) (per100g, perServing []map[string]any) {
+	switch basis {
+	case Per100g:
+		per100g = WithBasis(nutrition, Per100g)
+		if servingGrams > 0 {
+			perServing = ScaleNutrition(nutrition, servingGrams/100, PerServing)
+		}
+	case PerServing:
+		perServing = WithBasis(nutrition, PerServing)
+		if servingGrams > 0 {
+			per100g = ScaleNutrition(nutrition, 100/servingGrams, Per100g)
+		}
+	}
+	return per100g, perServing
+}
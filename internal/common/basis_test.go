@@ -0,0 +1,38 @@
+package common
+
+import "testing"
+
+func TestDualBasis(t *testing.T) {
+	nutrition := []map[string]any{{"name": "Protein", "amount": 10.0, "unit": "g"}}
+	tests := []struct {
+		basis          string
+		servingGrams   float64
+		per100g, serve float64 // -1 when nil
+	}{
+		{Per100g, 30, 10, 3},
+		{PerServing, 40, 25, 10},
+		{Per100g, 0, 10, -1},
+		{PerServing, 0, -1, 10},
+	}
+	amount := func(list []map[string]any, basis string) float64 {
+		if list == nil {
+			return -1
+		}
+		if list[0]["basis"] != basis {
+			t.Errorf("basis = %v, want %s", list[0]["basis"], basis)
+		}
+		return list[0]["amount"].(float64)
+	}
+	for _, tt := range tests {
+		per100g, perServing := DualBasis(nutrition, tt.basis, tt.servingGrams)
+		if got := amount(per100g, Per100g); got != tt.per100g {
+			t.Errorf("%s with %vg serving: per100g = %v, want %v", tt.basis, tt.servingGrams, got, tt.per100g)
+		}
+		if got := amount(perServing, PerServing); got != tt.serve {
+			t.Errorf("%s with %vg serving: perServing = %v, want %v", tt.basis, tt.servingGrams, got, tt.serve)
+		}
+	}
+	if _, ok := nutrition[0]["basis"]; ok || nutrition[0]["amount"] != 10.0 {
+		t.Errorf("input modified: %v", nutrition[0])
+	}
+}
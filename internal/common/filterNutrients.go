@@ -1,21 +1,102 @@
 package common
 
+import (
+	"github.com/Sush1sui/internal/nutrients"
+	"github.com/Sush1sui/internal/units"
+)
+
 type Nutrient struct {
+	// ID is the registry ID when the provider adapter already resolved it.
+	ID           string  `json:"-"`
+	Number       string  `json:"nutrientNumber,omitempty"`
 	NutrientName string  `json:"nutrientName"`
 	Value        float64 `json:"value"`
 	UnitName     string  `json:"unitName"`
 }
 
-func FilterNutrients(nutrients []Nutrient) []map[string]any {
+// Definition returns the registry entry of a provider nutrient, matched by
+// ID, USDA nutrient number or name.
+func (n Nutrient) Definition() (*nutrients.Definition, bool) {
+	return nutrients.Resolve(n.ID, n.Number, n.NutrientName)
+}
+
+// Canonical converts the nutrient to its registry unit, falling back to the
+// unit rules of the units package for nutrients the registry does not know.
+func (n Nutrient) Canonical() Nutrient {
+	def, ok := n.Definition()
+	if !ok {
+		n.Value, n.UnitName = units.Canonicalize(n.NutrientName, n.Value, n.UnitName)
+		return n
+	}
+	n.ID = def.ID
+	if v, err := units.ConvertNutrient(def.Name(nutrients.DefaultLocale), n.Value, n.UnitName, def.Unit); err == nil {
+		n.Value, n.UnitName = v, def.Unit
+	} else {
+		n.UnitName = units.Normalize(n.UnitName)
+	}
+	return n
+}
+
+// FilterNutrients converts each nutrient to its canonical unit. A reported
+// 0 is kept so it can be told apart from a nutrient the source does not
+// report; nutrient profiles decide whether zeros are shown. Nutrients found
+// in the registry carry their canonical "id".
+func FilterNutrients(list []Nutrient) []map[string]any {
 	var filtered []map[string]any
-	for _, n := range nutrients {
-		if n.Value >= 0.1 {
-			filtered = append(filtered, map[string]any{
+	for _, n := range list {
+		n = n.Canonical()
+		if n.Value >= 0 {
+			item := map[string]any{
 				"name":   n.NutrientName,
-				"amount": n.Value,
+				"amount": RoundTo(n.Value, 2),
 				"unit":   n.UnitName,
-			})
+			}
+			if n.ID != "" {
+				item["id"] = n.ID
+			}
+			filtered = append(filtered, item)
 		}
 	}
 	return filtered
-}
\ No newline at end of file
+}
+
+// NormalizeNutrients is the pipeline every provider's nutrients go through:
+// canonical units, display names, and one entry per
+// nutrient when a source reports it twice (e.g. energy in kcal and kJ).
+func NormalizeNutrients(list []Nutrient) []map[string]any {
+	return DedupeNutrition(RenameNutrition(FilterNutrients(list)))
+}
+
+// preferredOver maps a nutrient to the one kept instead of it when a source
+// reports both: vitamin A in µg RAE over vitamin A in IU.
+var preferredOver = map[string]string{"vitamin-a-iu": "vitamin-a"}
+
+// DedupeNutrition keeps the first entry for each nutrient ID, or name for
+// nutrients outside the registry, and drops entries a preferred nutrient
+// replaces.
+func DedupeNutrition(arr []map[string]any) []map[string]any {
+	present := map[string]bool{}
+	for _, item := range arr {
+		if id, ok := item["id"].(string); ok {
+			present[id] = true
+		}
+	}
+	seen := map[string]bool{}
+	var out []map[string]any
+	for _, item := range arr {
+		key, _ := item["id"].(string)
+		if present[preferredOver[key]] {
+			continue
+		}
+		if key == "" {
+			name, _ := item["name"].(string)
+			key = "name:" + name
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, item)
+	}
+	return out
+}
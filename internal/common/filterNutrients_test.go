@@ -0,0 +1,40 @@
+package common
+
+import "testing"
+
+func TestNormalizeVitaminA(t *testing.T) {
+	rae := Nutrient{Number: "320", NutrientName: "Vitamin A, RAE", Value: 50, UnitName: "UG"}
+	iu := Nutrient{Number: "318", NutrientName: "Vitamin A, IU", Value: 1000, UnitName: "IU"}
+	tests := []struct {
+		name string
+		list []Nutrient
+		want map[string]float64
+	}{
+		{"RAE and IU keep RAE", []Nutrient{iu, rae}, map[string]float64{"vitamin-a": 50}},
+		{"IU alone is not RAE", []Nutrient{iu}, map[string]float64{"vitamin-a-iu": 1000}},
+	}
+	for _, tt := range tests {
+		got := amounts(NormalizeNutrients(tt.list))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for id, v := range tt.want {
+			if got[id] != v {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestDedupeNutrition(t *testing.T) {
+	got := DedupeNutrition([]map[string]any{
+		{"id": "energy", "name": "Energy", "amount": 100.0},
+		{"id": "energy", "name": "Energy", "amount": 418.0},
+		{"name": "Lycopene", "amount": 1.0},
+		{"name": "Lycopene", "amount": 2.0},
+	})
+	if len(got) != 2 || got[0]["amount"] != 100.0 || got[1]["amount"] != 1.0 {
+		t.Errorf("DedupeNutrition = %v, want the first energy and lycopene", got)
+	}
+}
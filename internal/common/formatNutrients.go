@@ -1,36 +1,83 @@
 package common
 
 import (
-	"strings"
-
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
+	"github.com/Sush1sui/internal/nutrients"
+	"github.com/Sush1sui/internal/units"
 )
 
-func FormatNutriments(nutriments map[string]interface{}) []map[string]interface{} {
+// FormatNutriments picks the main Open Food Facts nutriments on a basis:
+// Per100g from the "_100g" keys or PerServing from the "_serving" keys, both
+// in grams (kcal for energy). The plain key and the label value under
+// "_value" in "_unit" are on the product's nutrition_data_per basis, so they
+// are only read when dataPer is the basis asked for, and the unit is only
+// trusted together with its own value. Keys are resolved through the
+// nutrient registry for names and units.
+func FormatNutriments(nutriments map[string]interface{}, basis, dataPer string) []map[string]interface{} {
     mainNutrients := []string{
         "energy-kcal", "fat", "saturated-fat", "trans-fat", "cholesterol",
         "carbohydrates", "sugars", "fiber", "proteins", "salt", "sodium",
         "vitamin-a", "vitamin-c", "vitamin-d", "calcium", "iron", "potassium",
     }
     var nutrientList []map[string]interface{}
-    titleCaser := cases.Title(language.English)
     for _, key := range mainNutrients {
-        if val, ok := nutriments[key]; ok {
-            amount, ok := val.(float64)
-            if !ok || amount <= 0 {
-                continue
-            }
-            unit := "g"
-            if u, ok := nutriments[key+"_unit"].(string); ok {
-                unit = u
+        amount, unit, ok := offNutriment(nutriments, key, basis, dataPer)
+        if !ok && key == "sodium" {
+            // derive sodium from salt when only salt is labelled
+            if salt, _, hasSalt := offNutriment(nutriments, "salt", basis, dataPer); hasSalt {
+                amount, unit, ok = units.SaltToSodium(salt), units.Gram, true
             }
-            nutrientList = append(nutrientList, map[string]interface{}{
-                "name":   titleCaser.String(strings.ReplaceAll(key, "-", " ")),
-                "amount": amount,
-                "unit":   unit,
-            })
         }
+        if !ok {
+            continue
+        }
+        def, known := nutrients.ByOFFKey(key)
+        if !known {
+            continue
+        }
+        if key == "vitamin-a" && units.Normalize(unit) == units.IU {
+            // a label value in IU is not RAE; see units.ConvertNutrient
+            def, _ = nutrients.ByID("vitamin-a-iu")
+        }
+        name := def.Name(nutrients.DefaultLocale)
+        if v, err := units.ConvertNutrient(name, amount, unit, def.Unit); err == nil {
+            amount, unit = v, def.Unit
+        }
+        if amount < 0 {
+            continue
+        }
+        nutrientList = append(nutrientList, map[string]interface{}{
+            "id":     def.ID,
+            "name":   name,
+            "amount": RoundTo(amount, 2),
+            "unit":   unit,
+        })
     }
     return nutrientList
-}
\ No newline at end of file
+}
+
+func offNutriment(nutriments map[string]interface{}, key, basis, dataPer string) (float64, string, bool) {
+    unit := units.Gram
+    if key == "energy-kcal" {
+        unit = units.Kcal
+    }
+    suffix := "_100g"
+    if basis == PerServing {
+        suffix = "_serving"
+    }
+    if v, ok := nutriments[key+suffix].(float64); ok {
+        return v, unit, true
+    }
+    if dataPer != basis {
+        return 0, "", false
+    }
+    if v, ok := nutriments[key+"_value"].(float64); ok {
+        if u, ok := nutriments[key+"_unit"].(string); ok && u != "" {
+            return v, u, true
+        }
+    }
+    v, ok := nutriments[key].(float64)
+    if !ok {
+        return 0, "", false
+    }
+    return v, unit, true
+}
@@ -0,0 +1,63 @@
+package common
+
+import "testing"
+
+func amounts(list []map[string]interface{}) map[string]float64 {
+	out := map[string]float64{}
+	for _, n := range list {
+		out[n["id"].(string)] = n["amount"].(float64)
+	}
+	return out
+}
+
+func TestFormatNutriments(t *testing.T) {
+	nutriments := map[string]interface{}{
+		"energy-kcal_100g":    250.0,
+		"energy-kcal_serving": 75.0,
+		"fat_100g":            10.0,
+		"salt_100g":           1.0,
+		"proteins":            9.0,
+		"proteins_value":      9.0,
+		"proteins_unit":       "g",
+		"vitamin-c_value":     30.0,
+		"vitamin-c_unit":      "mg",
+	}
+	tests := []struct {
+		name           string
+		basis, dataPer string
+		want           map[string]float64
+	}{
+		{"per 100 g, labelled per 100 g", Per100g, Per100g, map[string]float64{
+			"energy": 250, "fat": 10, "salt": 1, "sodium": 400, "protein": 9, "vitamin-c": 30,
+		}},
+		{"per 100 g, labelled per serving", Per100g, PerServing, map[string]float64{
+			"energy": 250, "fat": 10, "salt": 1, "sodium": 400,
+		}},
+		{"per serving, labelled per serving", PerServing, PerServing, map[string]float64{
+			"energy": 75, "protein": 9, "vitamin-c": 30,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := amounts(FormatNutriments(nutriments, tt.basis, tt.dataPer))
+			if len(got) != len(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("%s = %v, want %v", id, got[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatNutrimentsVitaminAIU(t *testing.T) {
+	got := amounts(FormatNutriments(map[string]interface{}{
+		"vitamin-a_value": 500.0,
+		"vitamin-a_unit":  "IU",
+	}, Per100g, Per100g))
+	if got["vitamin-a-iu"] != 500 || len(got) != 1 {
+		t.Errorf("vitamin A labelled in IU = %v, want vitamin-a-iu 500", got)
+	}
+}
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Sush1sui/internal/units"
+)
+
+// MealItem is one food mentioned in a free-text meal description.
+type MealItem struct {
+	Text     string  `json:"text"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Food     string  `json:"food"`
+}
+
+// Measure renders the quantity and unit as a portion measure, e.g. "2 slice".
+func (m MealItem) Measure() string {
+	return strings.TrimSpace(fmt.Sprintf("%v %s", m.Quantity, m.Unit))
+}
+
+// Countable reports whether the item is counted, with no unit or a count
+// word such as "slice", rather than weighed or measured by volume.
+func (m MealItem) Countable() bool {
+	return !units.IsMass(m.Unit) && !units.IsVolume(m.Unit)
+}
+
+var (
+	mealSeparators = regexp.MustCompile(`\s*(?:,|;|\n|&|\+|\band\b|\bwith\b|\bplus\b)\s*`)
+
+	numberWords = map[string]float64{
+		"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+		"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11,
+		"twelve": 12, "dozen": 12, "half": 0.5, "quarter": 0.25, "couple": 2,
+		"½": 0.5, "¼": 0.25, "¾": 0.75, "⅓": 1.0 / 3, "⅔": 2.0 / 3,
+	}
+
+	// mealUnits maps the household and metric units people type to the
+	// singular form portion descriptions use.
+	mealUnits = map[string]string{
+		"cup": "cup", "cups": "cup",
+		"tbsp": "tablespoon", "tablespoon": "tablespoon", "tablespoons": "tablespoon",
+		"tsp": "teaspoon", "teaspoon": "teaspoon", "teaspoons": "teaspoon",
+		"slice": "slice", "slices": "slice",
+		"piece": "piece", "pieces": "piece", "pc": "piece", "pcs": "piece",
+		"bowl": "bowl", "bowls": "bowl",
+		"glass": "glass", "glasses": "glass",
+		"can": "can", "cans": "can",
+		"bottle": "bottle", "bottles": "bottle",
+		"serving": "serving", "servings": "serving",
+		"handful": "handful", "handfuls": "handful",
+		"small": "small", "medium": "medium", "large": "large",
+		"g": "g", "gram": "g", "grams": "g", "kg": "kg", "mg": "mg",
+		"oz": "oz", "ounce": "oz", "ounces": "oz",
+		"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+		"ml": "ml", "l": "l", "liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	}
+
+	mealFillers = map[string]bool{"of": true, "some": true, "the": true, "x": true}
+)
+
+// ParseMeal splits text such as "2 eggs and a slice of toast with butter"
+// into items with a quantity, an optional unit and the food name. Parts
+// joined by "and", "&" or "with" stay one item when the words around the
+// join name a dish, such as "macaroni and cheese" or "fish & chips"; dish
+// reports known dish names and may be nil.
+func ParseMeal(text string, dish func(name string) bool) []MealItem {
+	text = strings.ToLower(text)
+	// seps[i] follows parts[i]
+	var parts, seps []string
+	start := 0
+	for _, loc := range mealSeparators.FindAllStringIndex(text, -1) {
+		parts = append(parts, text[start:loc[0]])
+		seps = append(seps, strings.TrimSpace(text[loc[0]:loc[1]]))
+		start = loc[1]
+	}
+	parts = append(parts, text[start:])
+
+	var items []MealItem
+	current := parts[0]
+	for i, next := range parts[1:] {
+		if joiner := dishJoiners[seps[i]]; joiner != "" && dish != nil && joinsDish(current, next, joiner, dish) {
+			current += " " + joiner + " " + next
+			continue
+		}
+		items = appendMealItem(items, current)
+		current = next
+	}
+	return appendMealItem(items, current)
+}
+
+func appendMealItem(items []MealItem, part string) []MealItem {
+	part = strings.Trim(part, " .!?")
+	if part == "" {
+		return items
+	}
+	if item, ok := parseMealItem(part); ok {
+		items = append(items, item)
+	}
+	return items
+}
+
+// maxDishWords bounds the words on each side of a join tried as a dish.
+const maxDishWords = 4
+
+// dishJoiners are the separators that may join the words of a dish name,
+// spelled the way dish names spell them.
+var dishJoiners = map[string]string{"and": "and", "&": "and", "with": "with"}
+
+// joinsDish reports whether words just before and after a join name a dish,
+// as "fish" and "chips" do in "2 plates of fish and chips".
+func joinsDish(before, after, joiner string, dish func(string) bool) bool {
+	left, right := strings.Fields(strings.Trim(before, " .!?")), strings.Fields(strings.Trim(after, " .!?"))
+	for a := 1; a <= len(left) && a <= maxDishWords; a++ {
+		for b := 1; b <= len(right) && b <= maxDishWords; b++ {
+			phrase := strings.Join(left[len(left)-a:], " ") + " " + joiner + " " + strings.Join(right[:b], " ")
+			if dish(phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parenthetical matches notes such as "(about 400 g)" in recipe lines.
+var parenthetical = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// ParseIngredient parses one recipe line such as "2 cups flour, sifted" as
+// a single item. Parenthetical notes and everything after the first comma,
+// usually preparation, are left out of the food name.
+func ParseIngredient(line string) (MealItem, bool) {
+	text := parenthetical.ReplaceAllString(strings.ToLower(line), "")
+	text, _, _ = strings.Cut(text, ",")
+	text = strings.Trim(text, " .!?")
+	if text == "" {
+		return MealItem{}, false
+	}
+	item, ok := parseMealItem(text)
+	item.Text = strings.TrimSpace(line)
+	return item, ok
+}
+
+func parseMealItem(text string) (MealItem, bool) {
+	qty, rest := ParseQuantity(text)
+	words := strings.Fields(rest)
+	if qty == 1 && len(words) > 0 {
+		if v, ok := numberWords[words[0]]; ok {
+			qty, words = v, words[1:]
+			// "half a cup", "a dozen eggs"
+			if len(words) > 0 {
+				if v, ok := numberWords[words[0]]; ok {
+					qty, words = qty*v, words[1:]
+				}
+			}
+		}
+	}
+
+	item := MealItem{Text: text, Quantity: RoundTo(qty, 3)}
+	if len(words) > 1 {
+		if unit, ok := mealUnits[words[0]]; ok {
+			item.Unit, words = unit, words[1:]
+		}
+	}
+	for len(words) > 1 && mealFillers[words[0]] {
+		words = words[1:]
+	}
+	item.Food = strings.Join(words, " ")
+	return item, item.Food != "" && !mealFillers[item.Food]
+}
@@ -0,0 +1,95 @@
+package common
+
+import "testing"
+
+func TestParseMeal(t *testing.T) {
+	dishes := map[string]bool{"macaroni and cheese": true, "fish and chips": true, "hot and sour soup": true}
+	dish := func(name string) bool { return dishes[name] }
+
+	tests := []struct {
+		text string
+		want []MealItem
+	}{
+		{"2 eggs and a slice of toast with butter", []MealItem{
+			{Text: "2 eggs", Quantity: 2, Food: "eggs"},
+			{Text: "a slice of toast", Quantity: 1, Unit: "slice", Food: "toast"},
+			{Text: "butter", Quantity: 1, Food: "butter"},
+		}},
+		{"half a cup of rice, 200g chicken breast; 1 1/2 cups milk", []MealItem{
+			{Text: "half a cup of rice", Quantity: 0.5, Unit: "cup", Food: "rice"},
+			{Text: "200g chicken breast", Quantity: 200, Unit: "g", Food: "chicken breast"},
+			{Text: "1 1/2 cups milk", Quantity: 1.5, Unit: "cup", Food: "milk"},
+		}},
+		{"a dozen oysters + two cans of soda", []MealItem{
+			{Text: "a dozen oysters", Quantity: 12, Food: "oysters"},
+			{Text: "two cans of soda", Quantity: 2, Unit: "can", Food: "soda"},
+		}},
+		{"a bowl of macaroni and cheese with a salad", []MealItem{
+			{Text: "a bowl of macaroni and cheese", Quantity: 1, Unit: "bowl", Food: "macaroni and cheese"},
+			{Text: "a salad", Quantity: 1, Food: "salad"},
+		}},
+		{"Fish & Chips and hot and sour soup.", []MealItem{
+			{Text: "fish and chips", Quantity: 1, Food: "fish and chips"},
+			{Text: "hot and sour soup", Quantity: 1, Food: "hot and sour soup"},
+		}},
+		{"and, , the", nil},
+	}
+	for _, tt := range tests {
+		got := ParseMeal(tt.text, dish)
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseMeal(%q) = %+v, want %+v", tt.text, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseMeal(%q)[%d] = %+v, want %+v", tt.text, i, got[i], tt.want[i])
+			}
+		}
+	}
+
+	if got := ParseMeal("macaroni and cheese", nil); len(got) != 2 {
+		t.Errorf("without dishes got %+v, want two items", got)
+	}
+}
+
+func TestMealItemMeasure(t *testing.T) {
+	tests := []struct {
+		item      MealItem
+		measure   string
+		countable bool
+	}{
+		{MealItem{Quantity: 2, Unit: "slice"}, "2 slice", true},
+		{MealItem{Quantity: 3}, "3", true},
+		{MealItem{Quantity: 200, Unit: "g"}, "200 g", false},
+		{MealItem{Quantity: 0.5, Unit: "cup"}, "0.5 cup", false},
+		{MealItem{Quantity: 1, Unit: "tablespoon"}, "1 tablespoon", false},
+	}
+	for _, tt := range tests {
+		if got := tt.item.Measure(); got != tt.measure {
+			t.Errorf("Measure() = %q, want %q", got, tt.measure)
+		}
+		if got := tt.item.Countable(); got != tt.countable {
+			t.Errorf("%q Countable() = %v, want %v", tt.measure, got, tt.countable)
+		}
+	}
+}
+
+func TestParseIngredient(t *testing.T) {
+	tests := []struct {
+		line string
+		want MealItem
+		ok   bool
+	}{
+		{"2 cups flour, sifted", MealItem{Text: "2 cups flour, sifted", Quantity: 2, Unit: "cup", Food: "flour"}, true},
+		{"1 can (400 g) chickpeas, drained and rinsed", MealItem{Text: "1 can (400 g) chickpeas, drained and rinsed", Quantity: 1, Unit: "can", Food: "chickpeas"}, true},
+		{"  Salt and pepper.", MealItem{Text: "Salt and pepper.", Quantity: 1, Food: "salt and pepper"}, true},
+		{"1/2 tsp baking soda", MealItem{Text: "1/2 tsp baking soda", Quantity: 0.5, Unit: "teaspoon", Food: "baking soda"}, true},
+		{"(optional), to taste", MealItem{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseIngredient(tt.line)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("ParseIngredient(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sush1sui/internal/units"
+)
+
+// FoodPortion is a household measure for a food with its weight in grams,
+// as listed in the FNDDS foodPortions of a USDA food.
+type FoodPortion struct {
+	Index       int     `json:"index"`
+	Description string  `json:"description"`
+	GramWeight  float64 `json:"gramWeight"`
+}
+
+// PortionInput is the portion a client asks for. Only one field is expected;
+// Grams wins over Index, which wins over Measure.
+type PortionInput struct {
+	Grams   float64 `json:"grams"`
+	Measure string  `json:"measure"`
+	Index   *int    `json:"index"`
+}
+
+// Label renders the portion the way servingSize is shown to clients.
+func (p FoodPortion) Label() string {
+	if p.Description == "" {
+		return fmt.Sprintf("%vg", RoundTo(p.GramWeight, 1))
+	}
+	return fmt.Sprintf("%s (%vg)", p.Description, RoundTo(p.GramWeight, 1))
+}
+
+// SortPortions orders portions by their FNDDS sequence number and assigns the
+// index clients use to pick one.
+func SortPortions(portions []FoodPortion, sequence []int) []FoodPortion {
+	idx := make([]int, len(portions))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return sequence[idx[a]] < sequence[idx[b]] })
+
+	sorted := make([]FoodPortion, 0, len(portions))
+	for _, i := range idx {
+		if portions[i].GramWeight <= 0 {
+			continue
+		}
+		p := portions[i]
+		p.Index = len(sorted)
+		sorted = append(sorted, p)
+	}
+	return sorted
+}
+
+// ResolvePortion picks the portion to scale nutrients to. Without input the
+// first FNDDS portion is used, or 100 g when the food has none.
+func ResolvePortion(portions []FoodPortion, in *PortionInput) (FoodPortion, error) {
+	if in == nil || (in.Grams <= 0 && in.Index == nil && strings.TrimSpace(in.Measure) == "") {
+		if len(portions) > 0 {
+			return portions[0], nil
+		}
+		return FoodPortion{Index: -1, GramWeight: 100}, nil
+	}
+
+	if in.Grams > 0 {
+		return FoodPortion{Index: -1, GramWeight: in.Grams}, nil
+	}
+
+	if in.Index != nil {
+		if *in.Index < 0 || *in.Index >= len(portions) {
+			return FoodPortion{}, fmt.Errorf("portion index %d is out of range (0-%d)", *in.Index, len(portions)-1)
+		}
+		return portions[*in.Index], nil
+	}
+
+	qty, unit := ParseQuantity(in.Measure)
+	if qty <= 0 {
+		return FoodPortion{}, fmt.Errorf("invalid portion measure %q", in.Measure)
+	}
+	if unit = strings.TrimSuffix(unit, "."); units.IsMass(unit) {
+		grams, _ := units.Convert(qty, unit, units.Gram)
+		return FoodPortion{Index: -1, Description: strings.TrimSpace(in.Measure), GramWeight: grams}, nil
+	}
+	if unit == "" {
+		// a bare count such as "2" means two of the default portion
+		base := FoodPortion{Index: -1, GramWeight: 100}
+		if len(portions) > 0 {
+			base = portions[0]
+		}
+		if qty != 1 && base.Description != "" {
+			if pQty, pUnit := ParseQuantity(base.Description); strings.ContainsAny(base.Description[:1], "0123456789") {
+				base.Description = fmt.Sprintf("%v %s", RoundTo(qty*pQty, 3), pUnit)
+			} else {
+				base.Description = fmt.Sprintf("%v x %s", qty, base.Description)
+			}
+		}
+		base.GramWeight *= qty
+		return base, nil
+	}
+	for _, p := range portions {
+		pQty, pUnit := ParseQuantity(p.Description)
+		if pQty <= 0 || !sameMeasure(unit, pUnit) {
+			continue
+		}
+		return FoodPortion{
+			Index:       p.Index,
+			Description: strings.TrimSpace(in.Measure),
+			GramWeight:  p.GramWeight * qty / pQty,
+		}, nil
+	}
+	if ml, ok := volume(qty, unit); ok {
+		// scale any portion given in a volume, so "250 ml" of milk follows
+		// its "1 cup" weight; without one, take the density of water
+		for _, p := range portions {
+			if pMl, ok := volume(ParseQuantity(p.Description)); ok && pMl > 0 {
+				return FoodPortion{
+					Index:       p.Index,
+					Description: strings.TrimSpace(in.Measure),
+					GramWeight:  p.GramWeight * ml / pMl,
+				}, nil
+			}
+		}
+		grams, _ := units.ServingGrams(ml, units.Milliliter)
+		return FoodPortion{Index: -1, Description: strings.TrimSpace(in.Measure), GramWeight: grams}, nil
+	}
+	return FoodPortion{}, fmt.Errorf("no portion matching %q for this food", in.Measure)
+}
+
+// volume converts a quantity to milliliters when its unit, or the unit
+// leading a description such as "cup, chopped" or "fl oz (no ice)", is a
+// volume.
+func volume(qty float64, unit string) (float64, bool) {
+	unit = strings.TrimSpace(unit)
+	if i := strings.IndexAny(unit, ",("); i >= 0 {
+		unit = strings.TrimSpace(unit[:i])
+	}
+	if ml, ok := units.Milliliters(qty, strings.TrimSuffix(unit, ".")); ok {
+		return ml, true
+	}
+	words := strings.Fields(unit)
+	if len(words) > 1 {
+		return units.Milliliters(qty, strings.TrimSuffix(words[0], "."))
+	}
+	return 0, false
+}
+
+// ScaleNutrients converts per-100g nutrient values to the given weight.
+func ScaleNutrients(nutrients []Nutrient, grams float64) []Nutrient {
+	scaled := make([]Nutrient, 0, len(nutrients))
+	for _, n := range nutrients {
+		n.Value = RoundTo(n.Value*grams/100, 2)
+		scaled = append(scaled, n)
+	}
+	return scaled
+}
+
+// ParseQuantity splits a measure like "1 1/2 cups" or "250ml" into its
+// numeric amount and the remaining unit text. Without a leading number the
+// amount is 1.
+func ParseQuantity(s string) (float64, string) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(s)))
+	if len(fields) > 0 {
+		// split a unit written against its number
+		f := fields[0]
+		i := strings.IndexFunc(f, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != '/' })
+		if i > 0 {
+			fields = append([]string{f[:i], f[i:]}, fields[1:]...)
+		}
+	}
+	qty, used := 0.0, 0
+	for used < len(fields) {
+		v, ok := parseNumber(fields[used])
+		if !ok {
+			break
+		}
+		qty += v
+		used++
+	}
+	if used == 0 {
+		qty = 1
+	}
+	return qty, strings.Join(fields[used:], " ")
+}
+
+// RoundTo rounds v to the given number of decimal places.
+func RoundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+func parseNumber(s string) (float64, bool) {
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err1 := strconv.ParseFloat(num, 64)
+		d, err2 := strconv.ParseFloat(den, 64)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// sameMeasure compares the leading word of two unit descriptions, ignoring a
+// plural "s" so "cups" matches "cup, chopped".
+func sameMeasure(a, b string) bool {
+	head := func(s string) string {
+		words := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' || r == '(' })
+		if len(words) == 0 {
+			return ""
+		}
+		return strings.TrimSuffix(words[0], "s")
+	}
+	ha, hb := head(a), head(b)
+	return ha != "" && ha == hb
+}
@@ -0,0 +1,123 @@
+package common
+
+import "testing"
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in   string
+		qty  float64
+		unit string
+	}{
+		{"1 cup", 1, "cup"},
+		{"1 1/2 cups", 1.5, "cups"},
+		{"250ml", 250, "ml"},
+		{"0.5 Cup, chopped", 0.5, "cup, chopped"},
+		{"slice", 1, "slice"},
+		{"2", 2, ""},
+		{"", 1, ""},
+	}
+	for _, tt := range tests {
+		qty, unit := ParseQuantity(tt.in)
+		if qty != tt.qty || unit != tt.unit {
+			t.Errorf("ParseQuantity(%q) = %v, %q; want %v, %q", tt.in, qty, unit, tt.qty, tt.unit)
+		}
+	}
+}
+
+func TestSortPortions(t *testing.T) {
+	portions := []FoodPortion{
+		{Description: "1 cup", GramWeight: 240},
+		{Description: "1 tbsp", GramWeight: 15},
+		{Description: "Quantity not specified", GramWeight: 0},
+	}
+	sorted := SortPortions(portions, []int{2, 1, 3})
+	if len(sorted) != 2 {
+		t.Fatalf("got %d portions, want 2 without the zero weight", len(sorted))
+	}
+	for i, want := range []string{"1 tbsp", "1 cup"} {
+		if sorted[i].Description != want || sorted[i].Index != i {
+			t.Errorf("portion %d = %q index %d; want %q index %d", i, sorted[i].Description, sorted[i].Index, want, i)
+		}
+	}
+}
+
+func TestResolvePortion(t *testing.T) {
+	portions := []FoodPortion{
+		{Index: 0, Description: "1 cup, chopped", GramWeight: 160},
+		{Index: 1, Description: "1 slice", GramWeight: 20},
+	}
+	one := 1
+	tests := []struct {
+		name   string
+		list   []FoodPortion
+		in     *PortionInput
+		grams  float64
+		desc   string
+		errors bool
+	}{
+		{"default", portions, nil, 160, "1 cup, chopped", false},
+		{"default without portions", nil, nil, 100, "", false},
+		{"grams", portions, &PortionInput{Grams: 42}, 42, "", false},
+		{"index", portions, &PortionInput{Index: &one}, 20, "1 slice", false},
+		{"index out of range", nil, &PortionInput{Index: &one}, 0, "", true},
+		{"matching measure", portions, &PortionInput{Measure: "2 cups"}, 320, "2 cups", false},
+		{"mass", portions, &PortionInput{Measure: "2 oz"}, 56.699, "2 oz", false},
+		{"count of the default", portions, &PortionInput{Measure: "3"}, 480, "3 cup, chopped", false},
+		{"unknown measure", portions, &PortionInput{Measure: "1 bowl"}, 0, "", true},
+		{"not a measure", portions, &PortionInput{Measure: "0 cups"}, 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ResolvePortion(tt.list, tt.in)
+			if tt.errors {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if RoundTo(p.GramWeight, 3) != tt.grams || p.Description != tt.desc {
+				t.Errorf("got %q %vg, want %q %vg", p.Description, p.GramWeight, tt.desc, tt.grams)
+			}
+		})
+	}
+}
+
+func TestResolveVolumePortion(t *testing.T) {
+	milk := []FoodPortion{
+		{Index: 0, Description: "1 cup", GramWeight: 244},
+		{Index: 1, Description: "1 fl oz", GramWeight: 30.5},
+	}
+	chopped := []FoodPortion{{Index: 0, Description: "1 cup, chopped", GramWeight: 160}}
+	tests := []struct {
+		name    string
+		list    []FoodPortion
+		measure string
+		grams   float64
+	}{
+		{"same measure", milk, "2 cups", 488},
+		{"metric volume from cups", milk, "250 ml", 257.832},
+		{"spoon from a described cup", chopped, "1 tbsp", 10},
+		{"water density without a volume portion", []FoodPortion{{Description: "1 slice", GramWeight: 20}}, "0.5 l", 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ResolvePortion(tt.list, &PortionInput{Measure: tt.measure})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if RoundTo(p.GramWeight, 3) != tt.grams || p.Description != tt.measure {
+				t.Errorf("got %q %vg, want %q %vg", p.Description, p.GramWeight, tt.measure, tt.grams)
+			}
+		})
+	}
+}
+
+func TestScaleNutrients(t *testing.T) {
+	scaled := ScaleNutrients([]Nutrient{{NutrientName: "Protein", Value: 12.5, UnitName: "g"}}, 40)
+	if scaled[0].Value != 5 {
+		t.Errorf("got %v g protein in 40 g, want 5", scaled[0].Value)
+	}
+}
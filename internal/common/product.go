@@ -0,0 +1,106 @@
+package common
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Product is a food as returned by any provider, with its nutrients already
+// normalized to the name/amount/unit maps the API responds with.
+type Product struct {
+	ID          string
+	Source      string
+	DataType    string
+	Barcode     string
+	Name        string
+	Brand       string
+	Ingredients string
+	ServingSize string
+	// ServingGrams is the serving weight in grams, 0 when unknown.
+	ServingGrams float64
+	// Basis is what Nutrition amounts refer to: Per100g or PerServing.
+	Basis     string
+	Nutrition []map[string]interface{}
+	// ServingNutrition is nutrition per serving the provider reported next
+	// to per-100g Nutrition; nil when it is derived from the serving weight.
+	ServingNutrition []map[string]interface{}
+	// LabelTags are the certification labels on the package, such as Open
+	// Food Facts' "en:vegan" or "en:halal" labels_tags.
+	LabelTags []string
+	// AnalysisTags are the provider's automated reading of the ingredients,
+	// such as Open Food Facts' "en:non-vegetarian"; they certify nothing.
+	AnalysisTags []string
+	// NovaGroup is the provider's NOVA processing group, 0 when unknown.
+	NovaGroup int
+	// Category is the provider's product category: the most specific Open
+	// Food Facts category tag or the USDA food category.
+	Category string
+	// Categories are all Open Food Facts categories_tags, broadest first.
+	Categories []string
+}
+
+// Data renders the product as the "data" object of a lookup response.
+// "nutrition" keeps the amounts as the provider reported them, tagged with
+// their basis; "per100g" and "perServing" are null when they cannot be
+// derived for lack of a serving weight. "serving" is the structured form of
+// servingSize, null when it cannot be parsed.
+func (p *Product) Data() map[string]interface{} {
+	per100g, perServing := p.DualNutrition()
+	data := map[string]interface{}{
+		"name":          p.Name,
+		"brand":         p.Brand,
+		"ingredients":   p.Ingredients,
+		"nutrition":     ChunkArray(WithBasis(p.Nutrition, p.Basis), 6),
+		"servingSize":   p.ServingSize,
+		"serving":       nil,
+		"servingWeight": nil,
+		"per100g":       nil,
+		"perServing":    nil,
+	}
+	if serving, ok := ParseServingSize(p.ServingSize); ok {
+		data["serving"] = serving
+	}
+	if grams := p.ServingWeight(); grams > 0 {
+		data["servingWeight"] = RoundTo(grams, 1)
+	}
+	if per100g != nil {
+		data["per100g"] = ChunkArray(per100g, 6)
+	}
+	if perServing != nil {
+		data["perServing"] = ChunkArray(perServing, 6)
+	}
+	return data
+}
+
+// DualNutrition returns the product's nutrition per 100 g and per serving.
+func (p *Product) DualNutrition() (per100g, perServing []map[string]interface{}) {
+	per100g, perServing = DualBasis(p.Nutrition, p.Basis, p.ServingWeight())
+	if len(p.ServingNutrition) > 0 {
+		perServing = WithBasis(p.ServingNutrition, PerServing)
+	}
+	return per100g, perServing
+}
+
+// ServingWeight is the serving in grams, parsed from the serving size when
+// the provider gave no structured weight.
+func (p *Product) ServingWeight() float64 {
+	if p.ServingGrams > 0 {
+		return p.ServingGrams
+	}
+	serving, _ := ParseServingSize(p.ServingSize)
+	return serving.Weight()
+}
+
+// DedupKey identifies the same product across providers: by barcode when
+// known, otherwise by its simplified name and brand.
+func (p *Product) DedupKey() string {
+	if code := strings.TrimLeft(p.Barcode, "0"); code != "" {
+		return "upc:" + code
+	}
+	simplify := func(s string) string {
+		return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}), " ")
+	}
+	return "name:" + simplify(p.Name) + "|" + simplify(p.Brand)
+}
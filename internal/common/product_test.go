@@ -0,0 +1,52 @@
+package common
+
+import "testing"
+
+func TestDedupKey(t *testing.T) {
+	tests := []struct {
+		a, b Product
+		same bool
+	}{
+		{Product{Barcode: "0012345678905", Name: "Cola"}, Product{Barcode: "12345678905", Name: "Cola Classic"}, true},
+		{Product{Name: "Peanut Butter, Creamy", Brand: "Jif"}, Product{Name: "peanut butter creamy", Brand: "JIF"}, true},
+		{Product{Name: "Peanut Butter", Brand: "Jif"}, Product{Name: "Peanut Butter", Brand: "Skippy"}, false},
+		{Product{Barcode: "000", Name: "Oats"}, Product{Name: "Oats"}, true},
+	}
+	for _, tt := range tests {
+		if same := tt.a.DedupKey() == tt.b.DedupKey(); same != tt.same {
+			t.Errorf("%q and %q: same = %v, want %v", tt.a.DedupKey(), tt.b.DedupKey(), same, tt.same)
+		}
+	}
+}
+
+func TestDualNutrition(t *testing.T) {
+	nutrition := []map[string]interface{}{{"name": "Sugars", "amount": 20.0, "unit": "g"}}
+	tests := []struct {
+		name           string
+		p              Product
+		per100g, serve float64 // -1 when nil
+	}{
+		{"serving weight given", Product{Basis: Per100g, Nutrition: nutrition, ServingGrams: 50}, 20, 10},
+		{"serving weight parsed", Product{Basis: Per100g, Nutrition: nutrition, ServingSize: "1 bar (40 g)"}, 20, 8},
+		{"per serving only", Product{Basis: PerServing, Nutrition: nutrition, ServingSize: "1 piece"}, -1, 20},
+		{"reported per serving", Product{
+			Basis: Per100g, Nutrition: nutrition, ServingGrams: 50,
+			ServingNutrition: []map[string]interface{}{{"name": "Sugars", "amount": 9.5, "unit": "g"}},
+		}, 20, 9.5},
+	}
+	amount := func(list []map[string]interface{}) float64 {
+		if list == nil {
+			return -1
+		}
+		return list[0]["amount"].(float64)
+	}
+	for _, tt := range tests {
+		per100g, perServing := tt.p.DualNutrition()
+		if got := amount(per100g); got != tt.per100g {
+			t.Errorf("%s: per100g = %v, want %v", tt.name, got, tt.per100g)
+		}
+		if got := amount(perServing); got != tt.serve {
+			t.Errorf("%s: perServing = %v, want %v", tt.name, got, tt.serve)
+		}
+	}
+}
@@ -1,31 +1,16 @@
 package common
 
-import "strings"
+import "github.com/Sush1sui/internal/nutrients"
 
+// RenameNutrition gives registry nutrients their English display name and
+// canonical "id"; nutrients outside the registry keep the provider's name.
 func RenameNutrition(arr []map[string]interface{}) []map[string]interface{} {
 	for i, item := range arr {
-		name := strings.ToLower(item["name"].(string))
-		switch name {
-		case "fatty acids, total saturated":
-			arr[i]["name"] = "Saturated Fats"
-		case "fatty acids, total trans":
-			arr[i]["name"] = "Trans Fats"
-		case "vitamin d (d2 + d3), international units":
-			arr[i]["name"] = "Vitamin D2 + D3"
-		case "potassium, k":
-			arr[i]["name"] = "Potassium"
-		case "sodium, na":
-			arr[i]["name"] = "Sodium"
-		case "calcium, ca":
-			arr[i]["name"] = "Calcium"
-		case "iron, fe":
-			arr[i]["name"] = "Iron"
-		case "fiber, total dietary":
-			arr[i]["name"] = "Dietary Fiber"
-		case "total sugars":
-			arr[i]["name"] = "Sugar"
-		case "carbohydrate, by difference":
-			arr[i]["name"] = "Carbohydrates"
+		id, _ := item["id"].(string)
+		name, _ := item["name"].(string)
+		if def, ok := nutrients.Resolve(id, "", name); ok {
+			arr[i]["id"] = def.ID
+			arr[i]["name"] = def.Name(nutrients.DefaultLocale)
 		}
 	}
 	return arr
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Sush1sui/internal/units"
+)
+
+// ServingQuantity is a serving size string broken into its household measure
+// ("2 piece") and metric equivalent ("30 g").
+type ServingQuantity struct {
+	Display     string  `json:"display"`
+	Count       float64 `json:"count,omitempty"`
+	Measure     string  `json:"measure,omitempty"`
+	Grams       float64 `json:"grams,omitempty"`
+	Milliliters float64 `json:"milliliters,omitempty"`
+}
+
+// Weight is the serving in grams, taking liquids at the density of water.
+func (q ServingQuantity) Weight() float64 {
+	if q.Grams > 0 {
+		return q.Grams
+	}
+	return q.Milliliters
+}
+
+var (
+	servingParens    = regexp.MustCompile(`[(\[]([^)\]]*)[)\]]`)
+	thousandsComma   = regexp.MustCompile(`(\d),(\d{3})\b`)
+	decimalComma     = regexp.MustCompile(`(\d),(\d)`)
+	unicodeFractions = strings.NewReplacer("½", " 1/2", "¼", " 1/4", "¾", " 3/4", "⅓", " 1/3", "⅔", " 2/3")
+)
+
+// ParseServingSize parses provider serving sizes such as "30g",
+// "1 cup (240g)", "2 pieces (30 g)", "1/2 cup", "250ml", "1,5 l" or USDA's
+// "240MLT". It reports false for empty or "N/A" sizes and for text with
+// neither a count nor a metric amount.
+func ParseServingSize(s string) (ServingQuantity, bool) {
+	q := ServingQuantity{Display: strings.TrimSpace(s)}
+	text := strings.ToLower(q.Display)
+	if text == "" || text == "n/a" {
+		return q, false
+	}
+	text = unicodeFractions.Replace(text)
+	text = thousandsComma.ReplaceAllString(text, "$1$2")
+	text = decimalComma.ReplaceAllString(text, "$1.$2")
+
+	// the metric amount in parentheses is what the label states, so it wins
+	// over one converted from the household measure
+	var parts []string
+	for _, m := range servingParens.FindAllStringSubmatch(text, -1) {
+		parts = append(parts, m[1])
+	}
+	parts = append(parts, servingParens.ReplaceAllString(text, " "))
+	for _, part := range parts {
+		part = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), "."))
+		if part == "" || !strings.ContainsAny(part[:1], "0123456789.") {
+			continue
+		}
+		qty, unit := ParseQuantity(part)
+		unit = strings.Trim(unit, " .,")
+		switch u := units.Normalize(unit); {
+		case units.IsMass(u):
+			if q.Grams == 0 {
+				q.Grams, _ = units.Convert(qty, u, units.Gram)
+				q.Grams = RoundTo(q.Grams, 2)
+			}
+		case u == units.Milliliter || u == units.Liter || u == "fl oz" || u == "fl. oz":
+			if q.Milliliters == 0 {
+				q.Milliliters = qty
+				switch u {
+				case units.Liter:
+					q.Milliliters = qty * 1000
+				case "fl oz", "fl. oz":
+					q.Milliliters = RoundTo(qty*29.5735, 1)
+				}
+			}
+		default:
+			if q.Count == 0 {
+				q.Count = RoundTo(qty, 3)
+				q.Measure = singularMeasure(unit)
+			}
+		}
+	}
+	return q, q.Count > 0 || q.Grams > 0 || q.Milliliters > 0
+}
+
+// singularMeasure maps a household unit to the singular spelling meal
+// parsing uses, falling back to trimming a plural "s".
+func singularMeasure(unit string) string {
+	if u, ok := mealUnits[unit]; ok {
+		return u
+	}
+	words := strings.Fields(unit)
+	if len(words) == 0 {
+		return ""
+	}
+	if u, ok := mealUnits[words[0]]; ok {
+		words[0] = u
+	} else if !strings.HasSuffix(words[0], "ss") {
+		words[0] = strings.TrimSuffix(words[0], "s")
+	}
+	return strings.Join(words, " ")
+}
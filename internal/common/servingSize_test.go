@@ -0,0 +1,51 @@
+package common
+
+import "testing"
+
+func TestParseServingSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ServingQuantity
+		ok   bool
+	}{
+		{"30g", ServingQuantity{Grams: 30}, true},
+		{"1 cup (240g)", ServingQuantity{Count: 1, Measure: "cup", Grams: 240}, true},
+		{"2 pieces (30 g)", ServingQuantity{Count: 2, Measure: "piece", Grams: 30}, true},
+		{"1/2 cup", ServingQuantity{Count: 0.5, Measure: "cup"}, true},
+		{"½ cup", ServingQuantity{Count: 0.5, Measure: "cup"}, true},
+		{"250ml", ServingQuantity{Milliliters: 250}, true},
+		{"1,5 l", ServingQuantity{Milliliters: 1500}, true},
+		{"240MLT", ServingQuantity{Milliliters: 240}, true},
+		{"12 fl oz (355 mL)", ServingQuantity{Milliliters: 355}, true},
+		{"8 fl oz", ServingQuantity{Milliliters: 236.6}, true},
+		{"1,000 mg", ServingQuantity{Grams: 1}, true},
+		{"3 glasses", ServingQuantity{Count: 3, Measure: "glass"}, true},
+		{"2 oz", ServingQuantity{Grams: 56.7}, true},
+		{"N/A", ServingQuantity{}, false},
+		{"", ServingQuantity{}, false},
+		{"one bar", ServingQuantity{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseServingSize(tt.in)
+		tt.want.Display = tt.in
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("ParseServingSize(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestServingWeight(t *testing.T) {
+	tests := []struct {
+		q    ServingQuantity
+		want float64
+	}{
+		{ServingQuantity{Grams: 30, Milliliters: 250}, 30},
+		{ServingQuantity{Milliliters: 250}, 250},
+		{ServingQuantity{Count: 1}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Weight(); got != tt.want {
+			t.Errorf("%+v Weight() = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package common
+
+// SumNutrients adds up the same nutrient across several foods, converting
+// each amount to its canonical unit first and keeping the order in which
+// each nutrient first appears. Registry nutrients are matched by ID, so
+// provider spellings of the same nutrient add up together.
+func SumNutrients(lists ...[]Nutrient) []Nutrient {
+	var total []Nutrient
+	index := map[string]int{}
+	for _, list := range lists {
+		for _, n := range list {
+			n = n.Canonical()
+			key := n.ID
+			if key == "" {
+				key = "name:" + n.NutrientName
+			}
+			key += "|" + n.UnitName
+			if i, ok := index[key]; ok {
+				total[i].Value = RoundTo(total[i].Value+n.Value, 2)
+				continue
+			}
+			index[key] = len(total)
+			total = append(total, n)
+		}
+	}
+	return total
+}
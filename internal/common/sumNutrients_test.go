@@ -0,0 +1,35 @@
+package common
+
+import "testing"
+
+func TestSumNutrients(t *testing.T) {
+	total := SumNutrients(
+		[]Nutrient{
+			{NutrientName: "Protein", Value: 10, UnitName: "g"},
+			{NutrientName: "Sodium, Na", Value: 200, UnitName: "mg"},
+		},
+		[]Nutrient{
+			{NutrientName: "Sodium, Na", Value: 0.1, UnitName: "g"},
+			{NutrientName: "Protein", Value: 2.5, UnitName: "g"},
+			{NutrientName: "Mystery", Value: 1, UnitName: "g"},
+		},
+	)
+	want := []struct {
+		name  string
+		value float64
+		unit  string
+	}{
+		{"Protein", 12.5, "g"},
+		{"Sodium, Na", 300, "mg"},
+		{"Mystery", 1, "g"},
+	}
+	if len(total) != len(want) {
+		t.Fatalf("got %d nutrients, want %d: %+v", len(total), len(want), total)
+	}
+	for i, w := range want {
+		n := total[i]
+		if n.NutrientName != w.name || n.Value != w.value || n.UnitName != w.unit {
+			t.Errorf("nutrient %d = %s %v %s, want %s %v %s", i, n.NutrientName, n.Value, n.UnitName, w.name, w.value, w.unit)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/Sush1sui/internal/nutrients"
+)
+
+// Directions a nutrient is better in.
+const (
+	Lower  = "lower"
+	Higher = "higher"
+)
+
+// DefaultTolerance is the relative difference within which amounts tie.
+const DefaultTolerance = 0.05
+
+// Rule says which way a nutrient is better. Amounts within Tolerance of the
+// best, relative to it, count as equally good.
+type Rule struct {
+	Nutrient  string  `json:"nutrient"`
+	Better    string  `json:"better"`
+	Tolerance float64 `json:"tolerance,omitempty"`
+}
+
+var (
+	mu sync.RWMutex
+	// rules follow the Dietary Guidelines: limit energy density, saturated
+	// and trans fat, sugars and sodium; favor fiber, protein and the
+	// under-consumed minerals and vitamin D.
+	rules = map[string]Rule{
+		"energy":        {Nutrient: "energy", Better: Lower},
+		"saturated-fat": {Nutrient: "saturated-fat", Better: Lower},
+		"trans-fat":     {Nutrient: "trans-fat", Better: Lower},
+		"cholesterol":   {Nutrient: "cholesterol", Better: Lower},
+		"sugars":        {Nutrient: "sugars", Better: Lower},
+		"added-sugars":  {Nutrient: "added-sugars", Better: Lower},
+		"sodium":        {Nutrient: "sodium", Better: Lower},
+		"salt":          {Nutrient: "salt", Better: Lower},
+		"fiber":         {Nutrient: "fiber", Better: Higher},
+		"protein":       {Nutrient: "protein", Better: Higher},
+		"potassium":     {Nutrient: "potassium", Better: Higher},
+		"calcium":       {Nutrient: "calcium", Better: Higher},
+		"iron":          {Nutrient: "iron", Better: Higher},
+		"vitamin-d":     {Nutrient: "vitamin-d", Better: Higher},
+	}
+)
+
+// LoadFile adds or replaces rules from a JSON array of {"nutrient",
+// "better", "tolerance"}. A rule whose better is "" removes the nutrient's
+// rule.
+func LoadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded []Rule
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for _, r := range loaded {
+		if _, ok := nutrients.ByID(r.Nutrient); !ok {
+			return fmt.Errorf("unknown nutrient %q in comparison rules", r.Nutrient)
+		}
+		if r.Better != "" && r.Better != Lower && r.Better != Higher {
+			return fmt.Errorf("comparison rule for %s: better must be %q or %q", r.Nutrient, Lower, Higher)
+		}
+		if r.Tolerance < 0 {
+			return fmt.Errorf("comparison rule for %s: tolerance must not be negative", r.Nutrient)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, r := range loaded {
+		if r.Better == "" {
+			delete(rules, r.Nutrient)
+			continue
+		}
+		rules[r.Nutrient] = r
+	}
+	return nil
+}
+
+// Product is one side of a comparison. Amounts are by nutrient registry ID
+// in canonical units; see nutrients.Amounts.
+type Product struct {
+	ID         string
+	Per100g    map[string]float64
+	PerServing map[string]float64
+}
+
+// Row is one nutrient across the compared products. The amount lists are
+// aligned with the products and hold nil where a product does not report
+// the nutrient. Differences are each product's amount less the first
+// product's.
+type Row struct {
+	Nutrient             string     `json:"nutrient"`
+	Name                 string     `json:"name"`
+	Unit                 string     `json:"unit"`
+	Per100g              []*float64 `json:"per100g"`
+	PerServing           []*float64 `json:"perServing"`
+	DifferencePer100g    []*float64 `json:"differencePer100g"`
+	DifferencePerServing []*float64 `json:"differencePerServing"`
+	// Better is the direction the nutrient is better in, "" when there is
+	// no rule for it.
+	Better string `json:"better,omitempty"`
+	// Best are the IDs of the products best in the nutrient per 100 g. It
+	// is empty when fewer than two products report it or all of them tie.
+	Best []string `json:"best"`
+}
+
+// Result is a nutrient table with the number of nutrients each product is
+// best in, by product ID.
+type Result struct {
+	Nutrients []Row          `json:"nutrients"`
+	Wins      map[string]int `json:"wins"`
+}
+
+// Compare aligns the nutrients any product reports, in registry order, and
+// judges each by its rule. Names are in the given locale.
+func Compare(products []Product, locale string) Result {
+	mu.RLock()
+	defer mu.RUnlock()
+	result := Result{Nutrients: []Row{}, Wins: map[string]int{}}
+	for _, p := range products {
+		result.Wins[p.ID] = 0
+	}
+	for _, d := range nutrients.All() {
+		row := Row{
+			Nutrient:   d.ID,
+			Name:       d.Name(locale),
+			Unit:       d.Unit,
+			Per100g:    column(products, d.ID, func(p Product) map[string]float64 { return p.Per100g }),
+			PerServing: column(products, d.ID, func(p Product) map[string]float64 { return p.PerServing }),
+			Best:       []string{},
+		}
+		if !reported(row.Per100g) && !reported(row.PerServing) {
+			continue
+		}
+		row.DifferencePer100g = differences(row.Per100g)
+		row.DifferencePerServing = differences(row.PerServing)
+		if rule, ok := rules[d.ID]; ok {
+			row.Better = rule.Better
+			for _, i := range best(row.Per100g, rule) {
+				row.Best = append(row.Best, products[i].ID)
+				result.Wins[products[i].ID]++
+			}
+		}
+		result.Nutrients = append(result.Nutrients, row)
+	}
+	return result
+}
+
+func column(products []Product, id string, amounts func(Product) map[string]float64) []*float64 {
+	values := make([]*float64, len(products))
+	for i, p := range products {
+		if v, ok := amounts(p)[id]; ok {
+			v = round(v)
+			values[i] = &v
+		}
+	}
+	return values
+}
+
+func reported(values []*float64) bool {
+	for _, v := range values {
+		if v != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// differences are relative to the first value, all nil when it is.
+func differences(values []*float64) []*float64 {
+	diffs := make([]*float64, len(values))
+	if len(values) == 0 || values[0] == nil {
+		return diffs
+	}
+	for i, v := range values {
+		if v != nil {
+			d := round(*v - *values[0])
+			diffs[i] = &d
+		}
+	}
+	return diffs
+}
+
+// best returns the indices of the values within tolerance of the best one,
+// or none when fewer than two are reported or all of them tie.
+func best(values []*float64, rule Rule) []int {
+	tolerance := rule.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+	var top float64
+	count := 0
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if count == 0 || (rule.Better == Lower && *v < top) || (rule.Better == Higher && *v > top) {
+			top = *v
+		}
+		count++
+	}
+	if count < 2 {
+		return nil
+	}
+	var indices []int
+	for i, v := range values {
+		if v != nil && math.Abs(*v-top) <= tolerance*math.Abs(top) {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == count {
+		return nil
+	}
+	return indices
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
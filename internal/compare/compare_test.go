@@ -0,0 +1,110 @@
+package compare
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func find(r Result, nutrient string) *Row {
+	for i := range r.Nutrients {
+		if r.Nutrients[i].Nutrient == nutrient {
+			return &r.Nutrients[i]
+		}
+	}
+	return nil
+}
+
+// values renders amounts with "-" for the unreported.
+func values(vs []*float64) string {
+	var out []string
+	for _, v := range vs {
+		if v == nil {
+			out = append(out, "-")
+		} else {
+			out = append(out, fmt.Sprint(*v))
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+var products = []Product{
+	{ID: "a", Per100g: map[string]float64{"sugars": 10, "fiber": 3, "sodium": 400, "protein": 8.004}, PerServing: map[string]float64{"sugars": 3}},
+	{ID: "b", Per100g: map[string]float64{"sugars": 5, "fiber": 3.1, "sodium": 390}},
+	{ID: "c", Per100g: map[string]float64{"sugars": 5.2, "fiber": 6, "sodium": 600}},
+}
+
+func TestCompare(t *testing.T) {
+	r := Compare(products, "en")
+
+	sugars := find(r, "sugars")
+	if sugars == nil {
+		t.Fatal("no sugars row")
+	}
+	if got := values(sugars.Per100g); got != "10 5 5.2" {
+		t.Errorf("sugars per 100 g = %s", got)
+	}
+	if got := values(sugars.PerServing); got != "3 - -" {
+		t.Errorf("sugars per serving = %s", got)
+	}
+	if got := values(sugars.DifferencePer100g); got != "0 -5 -4.8" {
+		t.Errorf("sugars differences = %s", got)
+	}
+	if sugars.Better != Lower || strings.Join(sugars.Best, ",") != "b,c" {
+		t.Errorf("sugars better %q, best %v; want lower, b and c within tolerance", sugars.Better, sugars.Best)
+	}
+
+	if fiber := find(r, "fiber"); strings.Join(fiber.Best, ",") != "c" {
+		t.Errorf("fiber best = %v, want c", fiber.Best)
+	}
+	if sodium := find(r, "sodium"); strings.Join(sodium.Best, ",") != "a,b" {
+		t.Errorf("sodium best = %v, want a and b within tolerance", sodium.Best)
+	}
+	protein := find(r, "protein")
+	if got := values(protein.Per100g); got != "8 - -" || len(protein.Best) != 0 {
+		t.Errorf("protein = %s best %v, want no best with one product", got, protein.Best)
+	}
+	if got := values(protein.DifferencePerServing); got != "- - -" {
+		t.Errorf("differences without the first product = %s", got)
+	}
+	if find(r, "energy") != nil {
+		t.Error("unreported energy has a row")
+	}
+	if r.Wins["a"] != 1 || r.Wins["b"] != 2 || r.Wins["c"] != 2 {
+		t.Errorf("wins = %v", r.Wins)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	saved := map[string]Rule{}
+	for k, v := range rules {
+		saved[k] = v
+	}
+	defer func() { rules = saved }()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	os.WriteFile(path, []byte(`[{"nutrient": "sodium", "better": "lower", "tolerance": 0.6}, {"nutrient": "fiber", "better": ""}]`), 0o644)
+	if err := LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	r := Compare(products, "en")
+	if sodium := find(r, "sodium"); len(sodium.Best) != 0 {
+		t.Errorf("sodium best = %v, want a tie within 60%%", sodium.Best)
+	}
+	if fiber := find(r, "fiber"); fiber.Better != "" || len(fiber.Best) != 0 {
+		t.Errorf("fiber still judged: %+v", fiber)
+	}
+
+	for _, bad := range []string{
+		`[{"nutrient": "unobtainium", "better": "lower"}]`,
+		`[{"nutrient": "sodium", "better": "sideways"}]`,
+		`[{"nutrient": "sodium", "better": "lower", "tolerance": -1}]`,
+	} {
+		os.WriteFile(path, []byte(bad), 0o644)
+		if err := LoadFile(path); err == nil {
+			t.Errorf("rules %s accepted", bad)
+		}
+	}
+}
@@ -3,18 +3,31 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	PORT                string
-	ServerURL           string
-	USDA_API_KEY        string
-	HUGGINGFACE_API_KEY string
-	NUTRITIONIX_API_KEY string
-	NUTRITIONIX_APP_ID  string
-	SUSHI_SECRET_KEY    string
+	PORT                   string
+	ServerURL              string
+	USDA_API_KEY           string
+	HUGGINGFACE_API_KEY    string
+	NUTRITIONIX_API_KEY    string
+	NUTRITIONIX_APP_ID     string
+	SUSHI_SECRET_KEY       string
+	DETECTION_BACKEND      string
+	DETECTION_MODEL        string
+	CACHE_TTL              time.Duration
+	FOOD_LIST_PATH         string
+	DV_TABLES_PATH         string
+	NUTRIENT_PROFILE       string
+	NUTRIENT_PROFILES_PATH string
+	LABEL_RULES_PATH       string
+	ADDITIVE_CONCERNS_PATH string
+	GI_TABLE_PATH          string
+	COMPARE_RULES_PATH     string
+	ALTERNATIVE_SCORE_PATH string
 }
 
 var Global *Config
@@ -57,13 +70,49 @@ func New() (*Config, error) {
 		return nil, fmt.Errorf("SUSHI_SECRET_KEY is not set in the environment variables")
 	}
 
+	detectionBackend := os.Getenv("DETECTION_BACKEND")
+	if detectionBackend == "" {
+		detectionBackend = "stub" // Whole image as one region
+	}
+
+	detectionModel := os.Getenv("DETECTION_MODEL")
+	if detectionModel == "" {
+		detectionModel = "facebook/detr-resnet-50"
+	}
+
+	cacheTTL := 24 * time.Hour
+	if v := os.Getenv("CACHE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("CACHE_TTL is not a valid duration: %w", err)
+		}
+		cacheTTL = ttl
+	}
+
+	nutrientProfile := os.Getenv("NUTRIENT_PROFILE")
+	if nutrientProfile == "" {
+		nutrientProfile = "full" // Every nutrient the source reports
+	}
+
 	return &Config{
-		PORT:                port,
-		ServerURL:           serverURL,
-		USDA_API_KEY:        usdaAPIKey,
-		HUGGINGFACE_API_KEY: huggingfaceAPIKey,
-		NUTRITIONIX_API_KEY: nutritionixAPIKey,
-		NUTRITIONIX_APP_ID:  nutritionixAppID,
-		SUSHI_SECRET_KEY:    sushiSecretKey,
+		PORT:                   port,
+		ServerURL:              serverURL,
+		USDA_API_KEY:           usdaAPIKey,
+		HUGGINGFACE_API_KEY:    huggingfaceAPIKey,
+		NUTRITIONIX_API_KEY:    nutritionixAPIKey,
+		NUTRITIONIX_APP_ID:     nutritionixAppID,
+		SUSHI_SECRET_KEY:       sushiSecretKey,
+		DETECTION_BACKEND:      detectionBackend,
+		DETECTION_MODEL:        detectionModel,
+		CACHE_TTL:              cacheTTL,
+		FOOD_LIST_PATH:         os.Getenv("FOOD_LIST_PATH"), // Optional autocomplete import
+		DV_TABLES_PATH:         os.Getenv("DV_TABLES_PATH"), // Optional %DV reference overrides
+		NUTRIENT_PROFILE:       nutrientProfile,
+		NUTRIENT_PROFILES_PATH: os.Getenv("NUTRIENT_PROFILES_PATH"), // Optional nutrient profile definitions
+		LABEL_RULES_PATH:       os.Getenv("LABEL_RULES_PATH"),       // Optional front-of-pack warning schemes
+		ADDITIVE_CONCERNS_PATH: os.Getenv("ADDITIVE_CONCERNS_PATH"), // Optional additive concern level overrides
+		GI_TABLE_PATH:          os.Getenv("GI_TABLE_PATH"),          // Optional glycemic index references
+		COMPARE_RULES_PATH:     os.Getenv("COMPARE_RULES_PATH"),     // Optional product comparison rules
+		ALTERNATIVE_SCORE_PATH: os.Getenv("ALTERNATIVE_SCORE_PATH"), // Optional healthier alternative score weights
 	}, nil
-}
\ No newline at end of file
+}
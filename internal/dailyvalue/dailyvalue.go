@@ -0,0 +1,173 @@
+package dailyvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Sush1sui/internal/nutrients"
+	"github.com/Sush1sui/internal/units"
+)
+
+// Reference is the daily amount a nutrient's %DV is computed against.
+type Reference struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+}
+
+// Set is a regulatory reference set: reference groups (adult, child,
+// pregnancy, ...) each mapping nutrient keys to their daily reference.
+type Set struct {
+	Name   string                          `json:"name"`
+	Groups map[string]map[string]Reference `json:"groups"`
+	// Fallback is the group consulted for nutrients a group does not define.
+	Fallback string `json:"fallback"`
+}
+
+// Table is the reference set and group selected for one request.
+type Table struct {
+	SetID string `json:"set"`
+	Group string `json:"group"`
+	set   *Set
+}
+
+const (
+	DefaultSet   = "us-fda-2016"
+	DefaultGroup = "adult"
+)
+
+var (
+	mu   sync.RWMutex
+	sets = defaultSets()
+)
+
+// LoadFile merges reference sets from a JSON file of the form
+// {"<set id>": {"name": ..., "fallback": ..., "groups": {"<group>": {"<nutrient key>": {"amount": .., "unit": ..}}}}}
+// into the bundled ones. Groups of an existing set are replaced one by one.
+func LoadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded map[string]*Set
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for id, s := range loaded {
+		existing, ok := sets[id]
+		if !ok {
+			sets[id] = s
+			continue
+		}
+		if s.Name != "" {
+			existing.Name = s.Name
+		}
+		if s.Fallback != "" {
+			existing.Fallback = s.Fallback
+		}
+		for group, refs := range s.Groups {
+			existing.Groups[group] = refs
+		}
+	}
+	return nil
+}
+
+// Select returns the table for a set and group, defaulting to the US FDA
+// 2016 adult values.
+func Select(setID, group string) (Table, error) {
+	if setID == "" {
+		setID = DefaultSet
+	}
+	if group == "" {
+		group = DefaultGroup
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	s, ok := sets[setID]
+	if !ok {
+		return Table{}, fmt.Errorf("unknown daily value set %q (available: %s)", setID, strings.Join(sortedKeys(sets), ", "))
+	}
+	if _, ok := s.Groups[group]; !ok {
+		return Table{}, fmt.Errorf("daily value set %q has no group %q (available: %s)", setID, group, strings.Join(sortedKeys(s.Groups), ", "))
+	}
+	return Table{SetID: setID, Group: group, set: s}, nil
+}
+
+// Reference looks up the daily reference of a nutrient by registry ID or
+// display name.
+func (t Table) Reference(nutrient string) (Reference, bool) {
+	if t.set == nil {
+		return Reference{}, false
+	}
+	key := Key(nutrient)
+	if ref, ok := t.set.Groups[t.Group][key]; ok {
+		return ref, true
+	}
+	if t.set.Fallback != "" && t.set.Fallback != t.Group {
+		ref, ok := t.set.Groups[t.set.Fallback][key]
+		return ref, ok
+	}
+	return Reference{}, false
+}
+
+// Percent computes the %DV of an amount, rounded to a whole percent.
+func (t Table) Percent(nutrient string, amount float64, unit string) (float64, bool) {
+	ref, ok := t.Reference(nutrient)
+	if !ok || ref.Amount <= 0 {
+		return 0, false
+	}
+	if def, ok := nutrients.Lookup(nutrient); ok {
+		nutrient = def.Name(nutrients.DefaultLocale)
+	}
+	v, err := units.ConvertNutrient(nutrient, amount, unit, ref.Unit)
+	if err != nil {
+		return 0, false
+	}
+	return math.Round(v / ref.Amount * 100), true
+}
+
+// Annotate adds "dv" (percent of the daily reference) to every nutrient map
+// with a known reference, matching on "id" when the map has one. Nutrients
+// with an unknown (null) amount get none.
+func (t Table) Annotate(nutrition []map[string]interface{}) {
+	for _, n := range nutrition {
+		name, _ := n["id"].(string)
+		if name == "" {
+			name, _ = n["name"].(string)
+		}
+		amount, ok := n["amount"].(float64)
+		if !ok {
+			continue
+		}
+		unit, _ := n["unit"].(string)
+		if pct, ok := t.Percent(name, amount, unit); ok {
+			n["dv"] = pct
+		}
+	}
+}
+
+// Key returns the reference table key for a nutrient: its registry ID, or
+// the lowercased name for nutrients outside the registry.
+func Key(nutrient string) string {
+	if def, ok := nutrients.Lookup(nutrient); ok {
+		return def.ID
+	}
+	return strings.ToLower(strings.TrimSpace(nutrient))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,100 @@
+package dailyvalue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	table, err := Select("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.SetID != DefaultSet || table.Group != DefaultGroup {
+		t.Errorf("default table = %s/%s", table.SetID, table.Group)
+	}
+	for _, sel := range [][2]string{{"eu-ri", "adult"}, {"ph-reni", "adult-female"}, {"us-fda-2016", "child"}} {
+		if _, err := Select(sel[0], sel[1]); err != nil {
+			t.Errorf("Select(%q, %q): %v", sel[0], sel[1], err)
+		}
+	}
+	for _, sel := range [][2]string{{"nope", ""}, {"eu-ri", "toddler"}} {
+		if _, err := Select(sel[0], sel[1]); err == nil {
+			t.Errorf("Select(%q, %q) succeeded", sel[0], sel[1])
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		set, group string
+		nutrient   string
+		amount     float64
+		unit       string
+		want       float64
+		ok         bool
+	}{
+		{"us-fda-2016", "adult", "sodium", 460, "mg", 20, true},
+		{"us-fda-2016", "adult", "Sodium, Na", 0.46, "g", 20, true},
+		{"us-fda-2016", "adult", "Vitamin D (D2 + D3)", 400, "IU", 50, true},
+		{"us-fda-2016", "child", "protein", 6.5, "g", 50, true},
+		// pregnancy falls back to the adult group for sodium
+		{"us-fda-2016", "pregnancy", "sodium", 230, "mg", 10, true},
+		{"eu-ri", "adult", "salt", 1.5, "g", 25, true},
+		{"ph-reni", "adult", "energy", 253, "kcal", 10, true},
+		{"us-fda-2016", "adult", "Caffeine", 100, "mg", 0, false},
+		{"us-fda-2016", "adult", "protein", 10, "kcal", 0, false},
+	}
+	for _, tt := range tests {
+		table, err := Select(tt.set, tt.group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := table.Percent(tt.nutrient, tt.amount, tt.unit)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s/%s Percent(%q, %v %s) = %v, %v; want %v, %v", tt.set, tt.group, tt.nutrient, tt.amount, tt.unit, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	table, _ := Select("", "")
+	nutrition := []map[string]interface{}{
+		{"id": "fiber", "name": "Dietary fibre", "amount": 7.0, "unit": "g"},
+		{"name": "Calcium, Ca", "amount": 130.0, "unit": "mg"},
+		{"name": "Iron", "amount": nil, "unit": "mg"},
+		{"name": "Caffeine", "amount": 80.0, "unit": "mg"},
+	}
+	table.Annotate(nutrition)
+	for i, want := range []interface{}{25.0, 10.0, nil, nil} {
+		if got := nutrition[i]["dv"]; got != want {
+			t.Errorf("%v dv = %v, want %v", nutrition[i]["name"], got, want)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dv.json")
+	content := `{"test-set": {"name": "Test", "fallback": "adult", "groups": {"adult": {"fiber": {"amount": 20, "unit": "g"}}, "teen": {}}}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	table, err := Select("test-set", "teen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := table.Percent("fiber", 5, "g"); !ok || got != 25 {
+		t.Errorf("loaded fiber %%DV = %v, %v; want 25", got, ok)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(path); err == nil {
+		t.Error("malformed file loaded")
+	}
+}
@@ -0,0 +1,167 @@
+package dailyvalue
+
+// defaultSets are the bundled reference sets. They can be extended or
+// overridden with LoadFile.
+func defaultSets() map[string]*Set {
+	return map[string]*Set{
+		// US FDA Daily Values from the 2016 Nutrition Facts label rule
+		// (21 CFR 101.9). Pregnancy/lactation only sets vitamins, minerals
+		// and protein; the rest comes from the adult group.
+		"us-fda-2016": {
+			Name:     "US FDA 2016 labeling Daily Values",
+			Fallback: "adult",
+			Groups: map[string]map[string]Reference{
+				"adult": {
+					"energy":        {2000, "kcal"},
+					"fat":           {78, "g"},
+					"saturated-fat": {20, "g"},
+					"cholesterol":   {300, "mg"},
+					"sodium":        {2300, "mg"},
+					"carbohydrates": {275, "g"},
+					"fiber":         {28, "g"},
+					"added-sugars":  {50, "g"},
+					"protein":       {50, "g"},
+					"vitamin-a":     {900, "µg"},
+					"vitamin-c":     {90, "mg"},
+					"vitamin-d":     {20, "µg"},
+					"vitamin-e":     {15, "mg"},
+					"calcium":       {1300, "mg"},
+					"iron":          {18, "mg"},
+					"potassium":     {4700, "mg"},
+					"magnesium":     {420, "mg"},
+					"phosphorus":    {1250, "mg"},
+					"zinc":          {11, "mg"},
+				},
+				"child": { // 1 through 3 years
+					"energy":        {1000, "kcal"},
+					"fat":           {39, "g"},
+					"saturated-fat": {10, "g"},
+					"cholesterol":   {300, "mg"},
+					"sodium":        {1500, "mg"},
+					"carbohydrates": {150, "g"},
+					"fiber":         {14, "g"},
+					"added-sugars":  {25, "g"},
+					"protein":       {13, "g"},
+					"vitamin-a":     {300, "µg"},
+					"vitamin-c":     {15, "mg"},
+					"vitamin-d":     {15, "µg"},
+					"vitamin-e":     {6, "mg"},
+					"calcium":       {700, "mg"},
+					"iron":          {7, "mg"},
+					"potassium":     {3000, "mg"},
+					"magnesium":     {80, "mg"},
+					"phosphorus":    {460, "mg"},
+					"zinc":          {3, "mg"},
+				},
+				"pregnancy": { // pregnant and lactating women
+					"protein":    {71, "g"},
+					"vitamin-a":  {1300, "µg"},
+					"vitamin-c":  {120, "mg"},
+					"vitamin-d":  {15, "µg"},
+					"vitamin-e":  {19, "mg"},
+					"calcium":    {1300, "mg"},
+					"iron":       {27, "mg"},
+					"potassium":  {5100, "mg"},
+					"magnesium":  {400, "mg"},
+					"phosphorus": {1250, "mg"},
+					"zinc":       {13, "mg"},
+				},
+			},
+		},
+		// EU Reference Intakes and NRVs, Regulation (EU) No 1169/2011
+		// Annex XIII. Sodium is derived from the 6 g salt reference.
+		"eu-ri": {
+			Name:     "EU Reference Intakes",
+			Fallback: "adult",
+			Groups: map[string]map[string]Reference{
+				"adult": {
+					"energy":        {2000, "kcal"},
+					"fat":           {70, "g"},
+					"saturated-fat": {20, "g"},
+					"carbohydrates": {260, "g"},
+					"sugars":        {90, "g"},
+					"protein":       {50, "g"},
+					"salt":          {6, "g"},
+					"sodium":        {2400, "mg"},
+					"vitamin-a":     {800, "µg"},
+					"vitamin-c":     {80, "mg"},
+					"vitamin-d":     {5, "µg"},
+					"vitamin-e":     {12, "mg"},
+					"calcium":       {800, "mg"},
+					"iron":          {14, "mg"},
+					"potassium":     {2000, "mg"},
+					"magnesium":     {375, "mg"},
+					"phosphorus":    {700, "mg"},
+					"zinc":          {10, "mg"},
+				},
+			},
+		},
+		// Philippine Recommended Energy and Nutrient Intakes from the 2015
+		// PDRI (FNRI-DOST). Adults are 19-29 years, children 7-9 years;
+		// fat and carbohydrate are derived from the AMDR upper bounds.
+		"ph-reni": {
+			Name:     "Philippine RENI (2015 PDRI)",
+			Fallback: "adult",
+			Groups: map[string]map[string]Reference{
+				"adult": {
+					"energy":        {2530, "kcal"},
+					"protein":       {71, "g"},
+					"fat":           {84, "g"},
+					"carbohydrates": {474, "g"},
+					"fiber":         {25, "g"},
+					"sodium":        {500, "mg"},
+					"potassium":     {4700, "mg"},
+					"vitamin-a":     {700, "µg"},
+					"vitamin-c":     {75, "mg"},
+					"vitamin-d":     {5, "µg"},
+					"vitamin-e":     {12, "mg"},
+					"calcium":       {750, "mg"},
+					"iron":          {12, "mg"},
+					"magnesium":     {250, "mg"},
+					"phosphorus":    {700, "mg"},
+					"zinc":          {6.5, "mg"},
+				},
+				"adult-female": {
+					"energy":        {1930, "kcal"},
+					"protein":       {62, "g"},
+					"fat":           {64, "g"},
+					"carbohydrates": {362, "g"},
+					"fiber":         {20, "g"},
+					"vitamin-a":     {600, "µg"},
+					"vitamin-c":     {70, "mg"},
+					"iron":          {28, "mg"},
+					"magnesium":     {215, "mg"},
+					"zinc":          {4.6, "mg"},
+				},
+				"child": {
+					"energy":        {1600, "kcal"},
+					"protein":       {29, "g"},
+					"fat":           {53, "g"},
+					"carbohydrates": {300, "g"},
+					"fiber":         {16, "g"},
+					"sodium":        {325, "mg"},
+					"potassium":     {3800, "mg"},
+					"vitamin-a":     {400, "µg"},
+					"vitamin-c":     {45, "mg"},
+					"vitamin-d":     {5, "µg"},
+					"vitamin-e":     {7, "mg"},
+					"calcium":       {700, "mg"},
+					"iron":          {9, "mg"},
+					"magnesium":     {170, "mg"},
+					"phosphorus":    {500, "mg"},
+					"zinc":          {4.8, "mg"},
+				},
+				"pregnancy": { // second and third trimester
+					"energy":    {2230, "kcal"},
+					"protein":   {89, "g"},
+					"vitamin-a": {800, "µg"},
+					"vitamin-c": {80, "mg"},
+					"calcium":   {800, "mg"},
+					"iron":      {38, "mg"},
+					"magnesium": {255, "mg"},
+					"zinc":      {6.5, "mg"},
+				},
+			},
+		},
+	}
+}
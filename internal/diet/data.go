@@ -0,0 +1,117 @@
+package diet
+
+import "regexp"
+
+// Ingredient kinds found by the dictionaries below.
+const (
+	meat        = "meat"
+	pork        = "pork"
+	insect      = "insect"
+	animal      = "animal"
+	alcohol     = "alcohol"
+	maybeAnimal = "maybe-animal"
+	// gelatin and rennet are animal-derived unless the label names a plant
+	// or microbial source, which the exclusions catch.
+	gelatin = "gelatin"
+	rennet  = "rennet"
+)
+
+// kindText describes each kind in reasons.
+var kindText = map[string]string{
+	meat:        "meat or meat-derived",
+	pork:        "pork-derived",
+	insect:      "insect-derived",
+	animal:      "animal-derived",
+	alcohol:     "alcoholic",
+	maybeAnimal: "possibly animal-derived",
+	gelatin:     "usually from pork or beef",
+	rennet:      "often from calves",
+	"milk":      "milk-derived",
+	"egg":       "egg-derived",
+	"seafood":   "fish or seafood",
+	"shellfish": "shellfish",
+}
+
+// dictionary maps lowercase ingredient terms to their kind.
+var dictionary = map[string]string{
+	"beef": meat, "veal": meat, "chicken": meat, "turkey": meat, "duck": meat, "goose": meat,
+	"lamb": meat, "mutton": meat, "goat": meat, "venison": meat, "rabbit": meat, "meat": meat,
+	"poultry": meat, "tallow": meat, "beef tallow": meat, "suet": meat, "animal fat": meat,
+	"animal shortening": meat, "chicken fat": meat, "beef fat": meat, "collagen": meat,
+	"bone broth": meat, "bone marrow": meat, "bone phosphate": meat, "liver": meat,
+	"meat extract": meat, "beef extract": meat, "chicken extract": meat, "isinglass": meat,
+	"baka": meat, "manok": meat, "carne": meat, "pollo": meat,
+
+	"pork": pork, "ham": pork, "bacon": pork, "lard": pork, "prosciutto": pork, "pancetta": pork,
+	"pepperoni": pork, "salami": pork, "chorizo": pork, "pork gelatin": pork, "pork fat": pork,
+	"porcine": pork, "swine": pork, "pig": pork, "baboy": pork, "lechon": pork,
+	"chicharron": pork, "tocino": pork, "longganisa": pork, "cerdo": pork,
+
+	"gelatin": gelatin, "gelatine": gelatin, "e441": gelatin,
+	"rennet": rennet, "animal rennet": rennet,
+
+	"carmine": insect, "cochineal": insect, "carminic acid": insect, "e120": insect,
+	"shellac": insect, "e904": insect,
+
+	"honey": animal, "beeswax": animal, "e901": animal, "royal jelly": animal,
+	"propolis": animal, "bee pollen": animal, "lanolin": animal, "e913": animal,
+
+	"alcohol": alcohol, "ethyl alcohol": alcohol, "ethanol": alcohol, "wine": alcohol,
+	"beer": alcohol, "rum": alcohol, "brandy": alcohol, "whisky": alcohol, "whiskey": alcohol,
+	"vodka": alcohol, "liqueur": alcohol, "sake": alcohol, "mirin": alcohol,
+	"cooking wine": alcohol,
+
+	"natural flavor": maybeAnimal, "natural flavors": maybeAnimal, "natural flavour": maybeAnimal,
+	"natural flavouring": maybeAnimal, "natural flavoring": maybeAnimal,
+	"mono- and diglycerides": maybeAnimal, "mono and diglycerides": maybeAnimal,
+	"monoglycerides": maybeAnimal, "diglycerides": maybeAnimal, "e471": maybeAnimal,
+	"glycerin": maybeAnimal, "glycerine": maybeAnimal, "glycerol": maybeAnimal, "e422": maybeAnimal,
+	"stearic acid": maybeAnimal, "e570": maybeAnimal, "vitamin d3": maybeAnimal,
+	"cholecalciferol": maybeAnimal, "l-cysteine": maybeAnimal, "e920": maybeAnimal,
+	"enzymes": maybeAnimal, "lipase": maybeAnimal, "pepsin": maybeAnimal,
+	"omega-3": maybeAnimal, "dha": maybeAnimal,
+}
+
+// exclusions are phrases whose terms do not count.
+var exclusions = []string{
+	"wine vinegar", "red wine vinegar", "white wine vinegar", "sugar alcohol", "alcohol-free",
+	"alcohol free", "non-alcoholic", "dealcoholized", "rum flavor", "rum flavour",
+	"vegetable gelatin", "plant gelatin", "microbial rennet", "vegetable rennet",
+	"vegetarian rennet", "non-animal rennet", "plant-based", "meat-free", "meatless",
+	"meat substitute", "meat analogue", "chicken-style", "beef-style", "pork-free",
+	"honeydew", "honey flavor", "honey flavour", "coconut meat", "nut meat", "ginger beer",
+	"root beer", "algal oil", "algae oil", "goat milk", "goat's milk", "goats milk",
+	"goat cheese", "lamb's lettuce", "from turkey",
+}
+
+// plantBased marks ingredients named as substitutes, such as "vegan
+// cheese", which are not checked for milk, egg or seafood.
+var plantBased = regexp.MustCompile(`\b(?:vegan|vegetarian|plant-based|dairy-free|egg-free)\b`)
+
+// cereals that are gluten-free themselves but usually contaminated.
+var contaminatedCereals = map[string]bool{"oat": true, "oats": true, "oatmeal": true, "avena": true}
+
+// certifications are OFF labels_tags that certify a diet.
+var certifications = map[string][]string{
+	Vegan:      {"en:vegan", "en:certified-vegan"},
+	Vegetarian: {"en:vegetarian", "en:vegan"},
+	Halal:      {"en:halal"},
+	Kosher:     {"en:kosher", "en:kosher-parve", "en:kosher-dairy", "en:kosher-meat"},
+	GlutenFree: {"en:gluten-free", "en:no-gluten", "en:certified-gluten-free"},
+	Keto:       {"en:keto", "en:ketogenic"},
+}
+
+// analysisTags are OFF ingredients_analysis_tags and what they say of a
+// diet.
+var analysisTags = map[string]map[string]string{
+	Vegan: {
+		"en:vegan":       Compatible,
+		"en:non-vegan":   Incompatible,
+		"en:maybe-vegan": Uncertain,
+	},
+	Vegetarian: {
+		"en:vegetarian":       Compatible,
+		"en:non-vegetarian":   Incompatible,
+		"en:maybe-vegetarian": Uncertain,
+	},
+}
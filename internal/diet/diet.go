@@ -0,0 +1,356 @@
+package diet
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Sush1sui/internal/allergens"
+	"github.com/Sush1sui/internal/glycemic"
+	"github.com/Sush1sui/internal/ingredients"
+)
+
+// Diets.
+const (
+	Vegan      = "vegan"
+	Vegetarian = "vegetarian"
+	Halal      = "halal"
+	Kosher     = "kosher-style"
+	GlutenFree = "gluten-free"
+	Keto       = "keto"
+)
+
+// Verdicts.
+const (
+	Compatible   = "compatible"
+	Incompatible = "incompatible"
+	Uncertain    = "uncertain"
+)
+
+// Net carbohydrates per 100 g up to which a food fits a ketogenic diet,
+// and up to which it may in small portions.
+const (
+	ketoLimit      = 5
+	ketoCloseLimit = 10
+)
+
+var diets = []struct{ id, name string }{
+	{Vegan, "Vegan"},
+	{Vegetarian, "Vegetarian"},
+	{Halal, "Halal"},
+	{Kosher, "Kosher-style"},
+	{GlutenFree, "Gluten-free"},
+	{Keto, "Keto"},
+}
+
+// Input is what diets are checked against.
+type Input struct {
+	Ingredients string
+	// LabelTags are Open Food Facts labels_tags, which certify a diet.
+	LabelTags []string
+	// AnalysisTags are Open Food Facts ingredients_analysis_tags, which only
+	// read the ingredients.
+	AnalysisTags []string
+	// Amounts are per 100 g by nutrient registry ID; see nutrients.Amounts.
+	Amounts map[string]float64
+}
+
+// Result is the verdict for one diet with the reasons for it.
+type Result struct {
+	Diet    string   `json:"diet"`
+	Name    string   `json:"name"`
+	Verdict string   `json:"verdict"`
+	Reasons []string `json:"reasons"`
+}
+
+// rules say which ingredient kinds rule a diet out and which leave it
+// uncertain.
+var rules = map[string]struct{ incompatible, uncertain []string }{
+	Vegan: {
+		[]string{meat, pork, insect, animal, gelatin, rennet, "milk", "egg", "seafood", "shellfish"},
+		[]string{maybeAnimal},
+	},
+	Vegetarian: {
+		[]string{meat, pork, insect, gelatin, "seafood", "shellfish"},
+		[]string{rennet, maybeAnimal},
+	},
+	Halal: {
+		[]string{pork, alcohol},
+		[]string{meat, gelatin, rennet, insect, maybeAnimal},
+	},
+	Kosher: {
+		[]string{pork, insect, "shellfish"},
+		[]string{gelatin, rennet},
+	},
+}
+
+var (
+	terms     = termPattern(keys(dictionary))
+	excluded  = termPattern(exclusions)
+	allergyOf = map[string]string{
+		"milk": "milk", "egg": "egg", "fish": "seafood", "crustaceans": "shellfish", "molluscs": "shellfish",
+	}
+)
+
+func keys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// termPattern matches any of the terms as whole words, optionally plural.
+func termPattern(list []string) *regexp.Regexp {
+	sorted := append([]string(nil), list...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, len(sorted))
+	for i, t := range sorted {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)(?:e?s)?\b`)
+}
+
+// Select resolves a list of diet IDs, all diets when ids is empty.
+func Select(ids []string) ([]string, error) {
+	all := make([]string, len(diets))
+	for i, d := range diets {
+		all[i] = d.id
+	}
+	if len(ids) == 0 {
+		return all, nil
+	}
+	for _, id := range ids {
+		if nameOf(id) == "" {
+			return nil, fmt.Errorf("unknown diet %q (available: %s)", id, strings.Join(all, ", "))
+		}
+	}
+	return ids, nil
+}
+
+func nameOf(id string) string {
+	for _, d := range diets {
+		if d.id == id {
+			return d.name
+		}
+	}
+	return ""
+}
+
+// finding is an ingredient of a given kind.
+type finding struct {
+	ingredient string
+	kind       string
+}
+
+// Check returns the verdicts for the given diets, in order.
+func Check(in Input, ids []string) []Result {
+	tree := ingredients.Parse(in.Ingredients)
+	findings := classify(tree)
+	labels, analysis := tagSet(in.LabelTags), tagSet(in.AnalysisTags)
+
+	results := make([]Result, 0, len(ids))
+	for _, id := range ids {
+		var r Result
+		switch id {
+		case Keto:
+			r = checkKeto(in.Amounts)
+		case GlutenFree:
+			r = checkGlutenFree(in.Ingredients, tree)
+		default:
+			r = checkIngredients(id, findings, len(tree.Ingredients) > 0)
+		}
+		r.Diet, r.Name = id, nameOf(id)
+		applyTags(&r, labels, analysis)
+		results = append(results, r)
+	}
+	return results
+}
+
+// classify finds the kinds of every ingredient in the tree, sub-ingredients
+// included.
+func classify(tree ingredients.Tree) []finding {
+	var findings []finding
+	seen := map[finding]bool{}
+	add := func(f finding) {
+		if !seen[f] {
+			seen[f] = true
+			findings = append(findings, f)
+		}
+	}
+	ingredients.Walk(tree.Ingredients, func(ing ingredients.Ingredient, _ int) {
+		text := strings.ToLower(ing.Text)
+		skip := excluded.FindAllStringIndex(text, -1)
+		for _, loc := range terms.FindAllStringIndex(text, -1) {
+			if within(loc, skip) {
+				continue
+			}
+			term := text[loc[0]:loc[1]]
+			for _, t := range []string{term, strings.TrimSuffix(term, "s"), strings.TrimSuffix(term, "es")} {
+				if kind, ok := dictionary[t]; ok {
+					add(finding{ing.Text, kind})
+					break
+				}
+			}
+		}
+		if plantBased.MatchString(text) {
+			return
+		}
+		for _, d := range allergens.Detect(ing.Text) {
+			if kind, ok := allergyOf[d.ID]; ok && d.Status == allergens.Contains {
+				add(finding{ing.Text, kind})
+			}
+		}
+	})
+	return findings
+}
+
+func within(loc []int, spans [][]int) bool {
+	for _, s := range spans {
+		if loc[0] >= s[0] && loc[1] <= s[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func checkIngredients(id string, findings []finding, listed bool) Result {
+	rule := rules[id]
+	var r Result
+	var incompatible, uncertain []string
+	hasMeat, hasMilk := false, false
+	for _, f := range findings {
+		reason := fmt.Sprintf("%s is %s", f.ingredient, kindText[f.kind])
+		switch {
+		case contains(rule.incompatible, f.kind):
+			incompatible = append(incompatible, reason)
+		case contains(rule.uncertain, f.kind):
+			uncertain = append(uncertain, reason)
+		}
+		hasMeat = hasMeat || f.kind == meat || f.kind == pork
+		hasMilk = hasMilk || f.kind == "milk"
+	}
+	if id == Kosher && hasMeat && hasMilk {
+		incompatible = append(incompatible, "combines meat and dairy")
+	}
+
+	switch {
+	case len(incompatible) > 0:
+		r.Verdict, r.Reasons = Incompatible, append(incompatible, uncertain...)
+	case len(uncertain) > 0:
+		r.Verdict, r.Reasons = Uncertain, uncertain
+	case !listed:
+		r.Verdict, r.Reasons = Uncertain, []string{"no ingredient list"}
+	default:
+		r.Verdict, r.Reasons = Compatible, []string{"no incompatible ingredients found"}
+	}
+	return r
+}
+
+// checkGlutenFree uses the EU gluten allergen terms. Oats are uncertain:
+// gluten-free themselves but usually contaminated.
+func checkGlutenFree(statement string, tree ingredients.Tree) Result {
+	var r Result
+	var incompatible, uncertain []string
+	for _, d := range allergens.Detect(statement, allergens.EU) {
+		if d.ID != "gluten" {
+			continue
+		}
+		for _, m := range d.Matches {
+			switch {
+			case m.Precautionary:
+				uncertain = append(uncertain, fmt.Sprintf("may contain %s", m.Term))
+			case contaminatedCereals[m.Term]:
+				uncertain = append(uncertain, fmt.Sprintf("%s may be contaminated with gluten", m.Term))
+			default:
+				incompatible = append(incompatible, fmt.Sprintf("%s contains gluten", m.Term))
+			}
+		}
+	}
+	switch {
+	case len(incompatible) > 0:
+		r.Verdict, r.Reasons = Incompatible, append(incompatible, uncertain...)
+	case len(uncertain) > 0:
+		r.Verdict, r.Reasons = Uncertain, uncertain
+	case len(tree.Ingredients) == 0:
+		r.Verdict, r.Reasons = Uncertain, []string{"no ingredient list"}
+	default:
+		r.Verdict, r.Reasons = Compatible, []string{"no gluten-containing ingredients found"}
+	}
+	return r
+}
+
+// checkKeto judges a food by its net carbohydrates per 100 g; see
+// glycemic.Count.
+func checkKeto(amounts map[string]float64) Result {
+	carbs, ok := glycemic.Count(amounts)
+	if !ok {
+		return Result{Verdict: Uncertain, Reasons: []string{"carbohydrates not reported"}}
+	}
+	reason := fmt.Sprintf("%g g net carbohydrates per 100 g", carbs.Net)
+	switch {
+	case carbs.Net <= ketoLimit:
+		return Result{Verdict: Compatible, Reasons: []string{reason}}
+	case carbs.Net <= ketoCloseLimit:
+		return Result{Verdict: Uncertain, Reasons: []string{reason + ", only in small portions"}}
+	}
+	return Result{Verdict: Incompatible, Reasons: []string{fmt.Sprintf("%s (limit %d g)", reason, ketoLimit)}}
+}
+
+// tagSet lowercases tags into a set.
+func tagSet(tags []string) map[string]bool {
+	set := map[string]bool{}
+	for _, t := range tags {
+		set[strings.ToLower(t)] = true
+	}
+	return set
+}
+
+// applyTags weighs Open Food Facts labels and ingredient analysis into a
+// result. A certification label makes an otherwise uncertain diet
+// compatible; a label contradicted by the ingredients leaves it uncertain.
+// Analysis tags only ever count as analysis, never as a label.
+func applyTags(r *Result, labels, analysis map[string]bool) {
+	certified := ""
+	for _, t := range certifications[r.Diet] {
+		if labels[t] {
+			certified = t
+			break
+		}
+	}
+	for tag, verdict := range analysisTags[r.Diet] {
+		if !analysis[tag] {
+			continue
+		}
+		reason := "Open Food Facts ingredient analysis: " + strings.TrimPrefix(tag, "en:")
+		switch {
+		case verdict == Incompatible && r.Verdict != Incompatible:
+			r.Verdict, r.Reasons = Incompatible, append([]string{reason}, r.Reasons...)
+		case verdict == Uncertain && r.Verdict == Compatible:
+			r.Verdict, r.Reasons = Uncertain, []string{reason}
+		case verdict == Compatible && r.Verdict == Uncertain && r.Reasons[0] == "no ingredient list":
+			r.Verdict, r.Reasons = Compatible, []string{reason}
+		}
+	}
+	if certified == "" {
+		return
+	}
+	reason := "labelled " + strings.TrimPrefix(certified, "en:")
+	switch r.Verdict {
+	case Incompatible:
+		r.Verdict = Uncertain
+		r.Reasons = append([]string{reason + ", but the ingredients disagree"}, r.Reasons...)
+	default:
+		r.Verdict, r.Reasons = Compatible, append([]string{reason}, r.Reasons...)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
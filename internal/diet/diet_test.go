@@ -0,0 +1,140 @@
+package diet
+
+import (
+	"strings"
+	"testing"
+)
+
+// verdicts renders results as "diet:verdict" pairs.
+func verdicts(results []Result) string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Diet+":"+r.Verdict)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestCheckIngredients(t *testing.T) {
+	religious := []string{Vegan, Vegetarian, Halal, Kosher}
+	tests := []struct {
+		name        string
+		ingredients string
+		want        string
+	}{
+		{"plant foods", "Rice, water, salt, sunflower oil",
+			"vegan:compatible vegetarian:compatible halal:compatible kosher-style:compatible"},
+		{"milk and eggs", "Flour, milk, eggs, sugar",
+			"vegan:incompatible vegetarian:compatible halal:compatible kosher-style:compatible"},
+		{"pork", "Pork, salt, dextrose",
+			"vegan:incompatible vegetarian:incompatible halal:incompatible kosher-style:incompatible"},
+		{"cheeseburger", "Beef, cheese (milk, salt, enzymes), bun",
+			"vegan:incompatible vegetarian:incompatible halal:uncertain kosher-style:incompatible"},
+		{"gelatin", "Sugar, gelatin, citric acid, carmine",
+			"vegan:incompatible vegetarian:incompatible halal:uncertain kosher-style:incompatible"},
+		{"wine sauce", "Tomatoes, red wine, herbs",
+			"vegan:compatible vegetarian:compatible halal:incompatible kosher-style:compatible"},
+		{"natural flavors", "Water, sugar, natural flavors",
+			"vegan:uncertain vegetarian:uncertain halal:uncertain kosher-style:compatible"},
+		// exclusions and substitutes
+		{"lookalikes", "Red wine vinegar, coconut meat, vegan cheese (cashews), microbial rennet",
+			"vegan:compatible vegetarian:compatible halal:compatible kosher-style:compatible"},
+		{"shrimp", "Shrimp, garlic, butter",
+			"vegan:incompatible vegetarian:incompatible halal:compatible kosher-style:incompatible"},
+		{"no list", "",
+			"vegan:uncertain vegetarian:uncertain halal:uncertain kosher-style:uncertain"},
+	}
+	for _, tt := range tests {
+		if got := verdicts(Check(Input{Ingredients: tt.ingredients}, religious)); got != tt.want {
+			t.Errorf("%s: got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckReasons(t *testing.T) {
+	r := Check(Input{Ingredients: "Chicken, cream, natural flavour"}, []string{Kosher, Vegetarian})
+	if r[0].Reasons[0] != "combines meat and dairy" {
+		t.Errorf("kosher reasons = %q", r[0].Reasons)
+	}
+	want := []string{"Chicken is meat or meat-derived", "Natural flavour is possibly animal-derived"}
+	if strings.Join(r[1].Reasons, "; ") != strings.Join(want, "; ") {
+		t.Errorf("vegetarian reasons = %q, want %q", r[1].Reasons, want)
+	}
+	if r[1].Name != "Vegetarian" {
+		t.Errorf("name = %q", r[1].Name)
+	}
+}
+
+func TestCheckGlutenFree(t *testing.T) {
+	tests := []struct {
+		ingredients string
+		want        string
+	}{
+		{"Rice flour, sugar, salt", Compatible},
+		{"Wheat flour, water, yeast", Incompatible},
+		{"Rolled oats, honey", Uncertain},
+		{"Corn, salt. May contain wheat.", Uncertain},
+		{"Buckwheat, water", Compatible},
+		{"", Uncertain},
+	}
+	for _, tt := range tests {
+		if got := Check(Input{Ingredients: tt.ingredients}, []string{GlutenFree})[0].Verdict; got != tt.want {
+			t.Errorf("%q: gluten-free %s, want %s", tt.ingredients, got, tt.want)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Input
+		want string
+	}{
+		{"certification settles uncertainty", Input{Ingredients: "Sugar, natural flavors", LabelTags: []string{"en:vegan"}},
+			"vegan:compatible vegetarian:compatible"},
+		{"certification contradicted by ingredients", Input{Ingredients: "Milk", LabelTags: []string{"EN:VEGAN"}},
+			"vegan:uncertain vegetarian:compatible"},
+		{"analysis rules out", Input{Ingredients: "Sugar", AnalysisTags: []string{"en:non-vegan", "en:vegetarian"}},
+			"vegan:incompatible vegetarian:compatible"},
+		{"analysis raises doubt", Input{Ingredients: "Sugar", AnalysisTags: []string{"en:maybe-vegan"}},
+			"vegan:uncertain vegetarian:compatible"},
+		{"analysis fills a missing list", Input{AnalysisTags: []string{"en:vegan", "en:vegetarian"}},
+			"vegan:compatible vegetarian:compatible"},
+		// analysis tags certify nothing
+		{"analysis is not a label", Input{Ingredients: "Milk", AnalysisTags: []string{"en:vegan"}},
+			"vegan:incompatible vegetarian:compatible"},
+	}
+	for _, tt := range tests {
+		if got := verdicts(Check(tt.in, []string{Vegan, Vegetarian})); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	all, err := Select(nil)
+	if err != nil || len(all) != len(diets) {
+		t.Errorf("Select(nil) = %v, %v", all, err)
+	}
+	if _, err := Select([]string{Vegan, "paleo"}); err == nil {
+		t.Error("unknown diet accepted")
+	}
+}
+
+func TestCheckKeto(t *testing.T) {
+	tests := []struct {
+		amounts map[string]float64
+		verdict string
+		reason  string
+	}{
+		{map[string]float64{"carbohydrates": 4}, Compatible, "4 g net carbohydrates per 100 g"},
+		{map[string]float64{"carbohydrates": 14, "fiber": 6}, Uncertain, "8 g net carbohydrates per 100 g, only in small portions"},
+		{map[string]float64{"carbohydrates": 60, "polyols": 40}, Incompatible, "20 g net carbohydrates per 100 g (limit 5 g)"},
+		{nil, Uncertain, "carbohydrates not reported"},
+	}
+	for _, tt := range tests {
+		r := Check(Input{Amounts: tt.amounts}, []string{Keto})[0]
+		if r.Verdict != tt.verdict || r.Reasons[0] != tt.reason {
+			t.Errorf("keto %v = %s %q, want %s %q", tt.amounts, r.Verdict, r.Reasons, tt.verdict, tt.reason)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package frontofpack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Label kinds.
+const (
+	TrafficLight = "traffic-light"
+	Warning      = "warning"
+)
+
+// Traffic light colors.
+const (
+	Green = "green"
+	Amber = "amber"
+	Red   = "red"
+)
+
+// Label is one front-of-pack element: a traffic light for a nutrient, or a
+// warning the product triggers.
+type Label struct {
+	Scheme    string  `json:"scheme"`
+	Kind      string  `json:"kind"`
+	ID        string  `json:"id"`
+	Nutrient  string  `json:"nutrient,omitempty"`
+	Value     float64 `json:"value"`
+	Unit      string  `json:"unit,omitempty"`
+	Color     string  `json:"color,omitempty"`
+	Text      string  `json:"text,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// Input is what labels are computed from.
+type Input struct {
+	// Amounts are per 100 g (100 ml for beverages) by nutrient registry ID,
+	// in canonical units; see nutrients.Amounts.
+	Amounts  map[string]float64
+	Beverage bool
+	// Sweeteners reports non-nutritive sweeteners in the ingredients.
+	Sweeteners bool
+}
+
+// Rule is a threshold warning: the product gets Text when the nutrient
+// exceeds the threshold for its kind. A zero threshold disables the rule
+// for that kind.
+type Rule struct {
+	ID       string  `json:"id"`
+	Text     string  `json:"text"`
+	Nutrient string  `json:"nutrient"`
+	Food     float64 `json:"food"`
+	Beverage float64 `json:"beverage"`
+}
+
+// Scheme is a labeling system. Threshold schemes are lists of rules and can
+// be configured; the UK traffic lights and Mexican NOM-051 seals are
+// computed in code.
+type Scheme struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+	apply func(Input) []Label
+}
+
+var (
+	mu      sync.RWMutex
+	schemes = map[string]*Scheme{
+		"uk-fsa": {Name: "UK FSA traffic lights", apply: ukTrafficLights},
+		"mexico": {Name: "Mexico NOM-051 warning seals", apply: mexicoSeals},
+		// Chile's Ley 20.606 final-phase limits.
+		"chile": {
+			Name: "Chile Ley 20.606 warning octagons",
+			Rules: []Rule{
+				{"high-energy", "ALTO EN CALORÍAS", "energy", 275, 70},
+				{"high-sugars", "ALTO EN AZÚCARES", "sugars", 10, 5},
+				{"high-saturated-fat", "ALTO EN GRASAS SATURADAS", "saturated-fat", 4, 3},
+				{"high-sodium", "ALTO EN SODIO", "sodium", 400, 100},
+			},
+		},
+		// Modelled on the Philippine FDA's proposed sugar and sodium
+		// warnings; override with LoadFile as the rules are finalized.
+		"ph-fda": {
+			Name: "Philippine FDA sugar and sodium warnings",
+			Rules: []Rule{
+				{"high-sugar", "HIGH IN SUGAR", "sugars", 10, 5},
+				{"high-sodium", "HIGH IN SODIUM", "sodium", 400, 100},
+			},
+		},
+	}
+)
+
+// LoadFile adds or replaces threshold schemes from a JSON file of the form
+// {"<scheme id>": {"name": ..., "rules": [{"id", "text", "nutrient", "food", "beverage"}]}}.
+// The computed schemes cannot be replaced.
+func LoadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded map[string]*Scheme
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for id := range loaded {
+		if existing, ok := schemes[id]; ok && existing.apply != nil {
+			return fmt.Errorf("label scheme %q is built in and cannot be replaced", id)
+		}
+	}
+	for id, s := range loaded {
+		schemes[id] = s
+	}
+	return nil
+}
+
+// Schemes resolves a list of scheme IDs, all schemes when ids is empty.
+func Schemes(ids []string) ([]string, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	all := make([]string, 0, len(schemes))
+	for id := range schemes {
+		all = append(all, id)
+	}
+	sort.Strings(all)
+	if len(ids) == 0 {
+		return all, nil
+	}
+	for _, id := range ids {
+		if _, ok := schemes[id]; !ok {
+			return nil, fmt.Errorf("unknown label scheme %q (available: %s)", id, strings.Join(all, ", "))
+		}
+	}
+	return ids, nil
+}
+
+// Compute returns the labels of the given schemes, in scheme order.
+func Compute(in Input, ids []string) []Label {
+	mu.RLock()
+	defer mu.RUnlock()
+	labels := []Label{}
+	for _, id := range ids {
+		s, ok := schemes[id]
+		if !ok {
+			continue
+		}
+		var got []Label
+		if s.apply != nil {
+			got = s.apply(in)
+		} else {
+			got = thresholdWarnings(in, s.Rules)
+		}
+		for i := range got {
+			got[i].Scheme = id
+		}
+		labels = append(labels, got...)
+	}
+	return labels
+}
+
+func thresholdWarnings(in Input, rules []Rule) []Label {
+	var labels []Label
+	for _, r := range rules {
+		threshold := r.Food
+		if in.Beverage {
+			threshold = r.Beverage
+		}
+		value, ok := in.Amounts[r.Nutrient]
+		if !ok || threshold <= 0 || value <= threshold {
+			continue
+		}
+		labels = append(labels, Label{
+			Kind:      Warning,
+			ID:        r.ID,
+			Nutrient:  r.Nutrient,
+			Value:     round(value),
+			Unit:      unitOf(r.Nutrient),
+			Text:      r.Text,
+			Threshold: threshold,
+		})
+	}
+	return labels
+}
@@ -0,0 +1,135 @@
+package frontofpack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ids renders labels as "id" for warnings and "id:color" for lights.
+func ids(labels []Label) string {
+	var out []string
+	for _, l := range labels {
+		if l.Color != "" {
+			out = append(out, l.ID+":"+l.Color)
+		} else {
+			out = append(out, l.ID)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func TestUKTrafficLights(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Input
+		want string
+	}{
+		{"crisps", Input{Amounts: map[string]float64{"fat": 30, "saturated-fat": 2.5, "sugars": 0.5, "sodium": 600}},
+			"fat:red saturated-fat:amber sugars:green salt:amber"},
+		{"boundaries are inclusive", Input{Amounts: map[string]float64{"fat": 3, "sugars": 22.5, "salt": 1.6}},
+			"fat:green sugars:amber salt:red"},
+		{"drink", Input{Beverage: true, Amounts: map[string]float64{"sugars": 10.6, "salt": 0.01}},
+			"sugars:amber salt:green"},
+	}
+	for _, tt := range tests {
+		if got := ids(Compute(tt.in, []string{"uk-fsa"})); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMexicoSeals(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Input
+		want string
+	}{
+		{"cookies", Input{Amounts: map[string]float64{"energy": 480, "sugars": 30, "saturated-fat": 8, "trans-fat": 0.6, "sodium": 350}},
+			"excess-energy excess-sugars excess-saturated-fat excess-trans-fat excess-sodium"},
+		{"added sugars win over total sugars", Input{Amounts: map[string]float64{"energy": 100, "sugars": 12, "added-sugars": 1}},
+			""},
+		{"sodium per kcal", Input{Amounts: map[string]float64{"energy": 50, "sodium": 60}},
+			"excess-sodium"},
+		{"soda", Input{Beverage: true, Amounts: map[string]float64{"energy": 42, "sugars": 10.6, "sodium": 10}},
+			"excess-energy excess-sugars"},
+		{"energy-free drink", Input{Beverage: true, Sweeteners: true, Amounts: map[string]float64{"energy": 0, "sodium": 50}},
+			"excess-sodium contains-sweeteners"},
+		{"drink with energy over 300 mg sodium", Input{Beverage: true, Amounts: map[string]float64{"energy": 400, "sodium": 320}},
+			"excess-energy excess-sodium"},
+	}
+	for _, tt := range tests {
+		if got := ids(Compute(tt.in, []string{"mexico"})); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestThresholdWarnings(t *testing.T) {
+	food := Input{Amounts: map[string]float64{"energy": 300, "sugars": 8, "saturated-fat": 5, "sodium": 400}}
+	drink := Input{Beverage: true, Amounts: map[string]float64{"energy": 71, "sugars": 8, "sodium": 90}}
+	tests := []struct {
+		scheme string
+		in     Input
+		want   string
+	}{
+		// limits must be exceeded, not reached
+		{"chile", food, "high-energy high-saturated-fat"},
+		{"chile", drink, "high-energy high-sugars"},
+		{"ph-fda", food, ""},
+		{"ph-fda", drink, "high-sugar"},
+	}
+	for _, tt := range tests {
+		labels := Compute(tt.in, []string{tt.scheme})
+		if got := ids(labels); got != tt.want {
+			t.Errorf("%s beverage=%v: got %q, want %q", tt.scheme, tt.in.Beverage, got, tt.want)
+		}
+		for _, l := range labels {
+			if l.Scheme != tt.scheme || l.Kind != Warning || l.Text == "" {
+				t.Errorf("%s label %+v", tt.scheme, l)
+			}
+		}
+	}
+}
+
+func TestSchemes(t *testing.T) {
+	all, err := Schemes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(all) != "[chile mexico ph-fda uk-fsa]" {
+		t.Errorf("Schemes(nil) = %v", all)
+	}
+	if _, err := Schemes([]string{"uk-fsa", "france"}); err == nil {
+		t.Error("unknown scheme accepted")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "labels.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if err := LoadFile(write(`{"test-fat": {"name": "Fat test", "rules": [{"id": "high-fat", "text": "HIGH FAT", "nutrient": "fat", "food": 20}]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	in := Input{Amounts: map[string]float64{"fat": 25}}
+	if got := ids(Compute(in, []string{"test-fat"})); got != "high-fat" {
+		t.Errorf("loaded scheme gave %q", got)
+	}
+	in.Beverage = true
+	if got := ids(Compute(in, []string{"test-fat"})); got != "" {
+		t.Errorf("rule without a beverage limit gave %q for a drink", got)
+	}
+
+	if err := LoadFile(write(`{"uk-fsa": {"name": "x"}}`)); err == nil {
+		t.Error("built-in scheme replaced")
+	}
+}
@@ -0,0 +1,151 @@
+package frontofpack
+
+import (
+	"math"
+
+	"github.com/Sush1sui/internal/nutrients"
+	"github.com/Sush1sui/internal/units"
+)
+
+// trafficLimits are the FSA upper bounds for green and amber, per 100 g of
+// food and per 100 ml of drink.
+var trafficLimits = []struct {
+	nutrient       string
+	green, amber   float64
+	drinkG, drinkA float64
+}{
+	{"fat", 3, 17.5, 1.5, 8.75},
+	{"saturated-fat", 1.5, 5, 0.75, 2.5},
+	{"sugars", 5, 22.5, 2.5, 11.25},
+	{"salt", 0.3, 1.5, 0.3, 0.75},
+}
+
+// ukTrafficLights colors fat, saturates, sugars and salt by the UK Food
+// Standards Agency front-of-pack guidance.
+func ukTrafficLights(in Input) []Label {
+	amounts := withSalt(in.Amounts)
+	var labels []Label
+	for _, l := range trafficLimits {
+		value, ok := amounts[l.nutrient]
+		if !ok {
+			continue
+		}
+		green, amber := l.green, l.amber
+		if in.Beverage {
+			green, amber = l.drinkG, l.drinkA
+		}
+		color := Red
+		switch {
+		case value <= green:
+			color = Green
+		case value <= amber:
+			color = Amber
+		}
+		labels = append(labels, Label{
+			Kind:     TrafficLight,
+			ID:       l.nutrient,
+			Nutrient: l.nutrient,
+			Value:    round(value),
+			Unit:     unitOf(l.nutrient),
+			Color:    color,
+		})
+	}
+	return labels
+}
+
+// mexicoSeals applies the NOM-051 (2020) warning seals. Sugar, fat and
+// sodium limits are relative to the product's energy; total sugars stand
+// in for free sugars when added sugars are not reported.
+func mexicoSeals(in Input) []Label {
+	a := in.Amounts
+	kcal, hasEnergy := a["energy"]
+	sugars, hasSugars := a["added-sugars"]
+	sugarID := "added-sugars"
+	if !hasSugars {
+		sugars, hasSugars = a["sugars"]
+		sugarID = "sugars"
+	}
+
+	var labels []Label
+	seal := func(id, text, nutrient string, value, threshold float64, unit string) {
+		labels = append(labels, Label{
+			Kind:      Warning,
+			ID:        id,
+			Nutrient:  nutrient,
+			Value:     round(value),
+			Unit:      unit,
+			Text:      text,
+			Threshold: threshold,
+		})
+	}
+
+	if hasEnergy {
+		switch {
+		case !in.Beverage && kcal >= 275:
+			seal("excess-energy", "EXCESO CALORÍAS", "energy", kcal, 275, units.Kcal)
+		case in.Beverage && kcal >= 70:
+			seal("excess-energy", "EXCESO CALORÍAS", "energy", kcal, 70, units.Kcal)
+		case in.Beverage && hasSugars && sugars*4 >= 8:
+			seal("excess-energy", "EXCESO CALORÍAS", sugarID, sugars*4, 8, units.Kcal)
+		}
+	}
+	if hasEnergy && kcal > 0 {
+		percent := func(grams, kcalPerGram float64) float64 { return grams * kcalPerGram / kcal * 100 }
+		if hasSugars && percent(sugars, 4) >= 10 {
+			seal("excess-sugars", "EXCESO AZÚCARES", sugarID, percent(sugars, 4), 10, "% energy")
+		}
+		if sat, ok := a["saturated-fat"]; ok && percent(sat, 9) >= 10 {
+			seal("excess-saturated-fat", "EXCESO GRASAS SATURADAS", "saturated-fat", percent(sat, 9), 10, "% energy")
+		}
+		if trans, ok := a["trans-fat"]; ok && percent(trans, 9) >= 1 {
+			seal("excess-trans-fat", "EXCESO GRASAS TRANS", "trans-fat", percent(trans, 9), 1, "% energy")
+		}
+	}
+	if sodium, ok := a["sodium"]; ok {
+		switch {
+		case in.Beverage && (!hasEnergy || kcal == 0) && sodium >= 45:
+			seal("excess-sodium", "EXCESO SODIO", "sodium", sodium, 45, units.Milligram)
+		// 300 mg applies to every product, beverages with energy included
+		case sodium >= 300:
+			seal("excess-sodium", "EXCESO SODIO", "sodium", sodium, 300, units.Milligram)
+		case hasEnergy && kcal > 0 && sodium/kcal >= 1:
+			seal("excess-sodium", "EXCESO SODIO", "sodium", sodium/kcal, 1, "mg/kcal")
+		}
+	}
+	if in.Sweeteners {
+		labels = append(labels, Label{
+			Kind: Warning,
+			ID:   "contains-sweeteners",
+			Text: "CONTIENE EDULCORANTES, NO RECOMENDABLE EN NIÑOS",
+		})
+	}
+	return labels
+}
+
+// withSalt adds salt derived from sodium when salt is not reported.
+func withSalt(amounts map[string]float64) map[string]float64 {
+	if _, ok := amounts["salt"]; ok {
+		return amounts
+	}
+	sodium, ok := amounts["sodium"]
+	if !ok {
+		return amounts
+	}
+	out := make(map[string]float64, len(amounts)+1)
+	for k, v := range amounts {
+		out[k] = v
+	}
+	out["salt"] = units.SodiumToSalt(sodium) / 1000
+	return out
+}
+
+func unitOf(nutrient string) string {
+	if d, ok := nutrients.ByID(nutrient); ok {
+		return d.Unit
+	}
+	return ""
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,115 @@
+package glycemic
+
+// references are glycemic indices (glucose = 100) from the International
+// Tables of Glycemic Index and Glycemic Load Values (Atkinson et al. 2008,
+// 2021), rounded to representative values for each food. Terms include the
+// Food-101 labels returned by the food scanner. FDCIDs are the SR Legacy
+// foods that are the reference food itself; other foods match by name.
+var references = []Reference{
+	// Breads and bakery
+	{Name: "White wheat bread", GI: 75, Terms: []string{"white bread", "bread", "pandesal", "toast", "bagel", "baguette"}},
+	{Name: "Whole wheat bread", GI: 74, Terms: []string{"whole wheat bread", "wholemeal bread", "whole grain bread", "wheat bread"}},
+	{Name: "Rye bread", GI: 58, Terms: []string{"rye bread", "pumpernickel"}},
+	{Name: "Sourdough bread", GI: 54, Terms: []string{"sourdough"}},
+	{Name: "Flatbread", GI: 66, Terms: []string{"tortilla", "pita", "naan", "chapati", "roti", "flatbread"}},
+	{Name: "Corn tortilla", GI: 46, Terms: []string{"corn tortilla", "tortilla chips", "nachos"}},
+	{Name: "Croissant", GI: 70, Terms: []string{"croissant"}},
+	{Name: "Doughnut", GI: 76, Terms: []string{"doughnut", "donut"}},
+	{Name: "Muffin", GI: 60, Terms: []string{"muffin", "cupcake"}},
+	{Name: "Pancakes", GI: 66, Terms: []string{"pancake", "hotcake"}},
+	{Name: "Waffles", GI: 76, Terms: []string{"waffle"}},
+	{Name: "Sponge cake", GI: 46, Terms: []string{"cake", "sponge cake"}},
+	{Name: "Chocolate cake", GI: 38, Terms: []string{"chocolate cake"}},
+	{Name: "Crackers", GI: 70, Terms: []string{"cracker", "water cracker", "saltine"}},
+	{Name: "Rice crackers", GI: 87, Terms: []string{"rice cracker", "rice cake"}},
+	{Name: "Cookies", GI: 55, Terms: []string{"cookie", "biscuit"}},
+
+	// Grains, pasta and noodles
+	{Name: "White rice, boiled", GI: 73, Terms: []string{"rice", "white rice", "steamed rice", "kanin"}},
+	{Name: "Brown rice, boiled", GI: 68, Terms: []string{"brown rice"}},
+	{Name: "Basmati rice", GI: 58, Terms: []string{"basmati"}},
+	{Name: "Glutinous rice", GI: 92, Terms: []string{"glutinous rice", "sticky rice", "malagkit"}},
+	{Name: "Fried rice", GI: 70, Terms: []string{"fried rice", "sinangag"}},
+	{Name: "Sushi", GI: 55, Terms: []string{"sushi", "sashimi roll"}},
+	{Name: "Rice porridge", GI: 78, Terms: []string{"congee", "arroz caldo", "lugaw", "rice porridge"}},
+	{Name: "Spaghetti, white, boiled", GI: 49, Terms: []string{"spaghetti", "pasta", "macaroni", "penne", "fettuccine", "lasagna", "ravioli", "linguine"}},
+	{Name: "Whole wheat spaghetti", GI: 48, Terms: []string{"whole wheat pasta", "whole wheat spaghetti", "wholemeal pasta"}},
+	{Name: "Rice noodles", GI: 53, Terms: []string{"rice noodle", "bihon", "pho", "pad thai", "vermicelli"}},
+	{Name: "Udon noodles", GI: 55, Terms: []string{"udon"}},
+	{Name: "Instant noodles", GI: 47, Terms: []string{"instant noodle", "ramen", "noodle", "pancit", "canton"}},
+	{Name: "Couscous", GI: 65, Terms: []string{"couscous"}},
+	{Name: "Quinoa", GI: 53, Terms: []string{"quinoa"}, FDCIDs: []int{168917}},
+	{Name: "Barley", GI: 28, Terms: []string{"barley", "pearl barley"}},
+	{Name: "Bulgur", GI: 48, Terms: []string{"bulgur"}},
+	{Name: "Sweet corn", GI: 52, Terms: []string{"corn", "sweet corn", "mais"}},
+	{Name: "Popcorn", GI: 65, Terms: []string{"popcorn"}},
+
+	// Breakfast cereals
+	{Name: "Cornflakes", GI: 81, Terms: []string{"cornflakes", "corn flakes"}},
+	{Name: "Rolled oat porridge", GI: 55, Terms: []string{"oatmeal", "porridge", "rolled oats", "oats"}},
+	{Name: "Instant oat porridge", GI: 79, Terms: []string{"instant oats", "instant oatmeal"}},
+	{Name: "Muesli", GI: 57, Terms: []string{"muesli", "granola"}},
+	{Name: "Bran cereal", GI: 43, Terms: []string{"bran flakes", "all-bran", "bran cereal"}},
+
+	// Starchy vegetables
+	{Name: "Potato, boiled", GI: 78, Terms: []string{"potato", "boiled potato"}},
+	{Name: "Mashed potato, instant", GI: 87, Terms: []string{"mashed potato", "instant mashed potato"}},
+	{Name: "French fries", GI: 63, Terms: []string{"french fries", "fries", "chips"}},
+	{Name: "Potato crisps", GI: 56, Terms: []string{"potato chips", "potato crisps", "crisps"}},
+	{Name: "Sweet potato", GI: 63, Terms: []string{"sweet potato", "kamote", "yam"}},
+	{Name: "Taro", GI: 53, Terms: []string{"taro", "gabi"}},
+	{Name: "Cassava", GI: 46, Terms: []string{"cassava", "kamoteng kahoy", "tapioca"}},
+	{Name: "Pumpkin", GI: 64, Terms: []string{"pumpkin", "kalabasa", "squash"}},
+	{Name: "Carrots, boiled", GI: 39, Terms: []string{"carrot"}},
+
+	// Legumes and nuts
+	{Name: "Chickpeas", GI: 28, Terms: []string{"chickpea", "garbanzo", "hummus"}, FDCIDs: []int{173757}},
+	{Name: "Kidney beans", GI: 24, Terms: []string{"kidney bean", "red bean"}},
+	{Name: "Lentils", GI: 32, Terms: []string{"lentil", "dal", "dhal"}, FDCIDs: []int{172421}},
+	{Name: "Soya beans", GI: 16, Terms: []string{"soybean", "soya bean", "edamame"}},
+	{Name: "Baked beans", GI: 40, Terms: []string{"baked beans"}},
+	{Name: "Mung beans", GI: 31, Terms: []string{"mung bean", "monggo"}},
+	{Name: "Peanuts", GI: 14, Terms: []string{"peanut"}, FDCIDs: []int{172430}},
+	{Name: "Cashew nuts", GI: 25, Terms: []string{"cashew"}},
+
+	// Fruit and juices
+	{Name: "Apple", GI: 36, Terms: []string{"apple"}, FDCIDs: []int{171688}},
+	{Name: "Apple juice", GI: 41, Terms: []string{"apple juice"}},
+	{Name: "Orange", GI: 43, Terms: []string{"orange", "mandarin", "dalandan"}, FDCIDs: []int{169097}},
+	{Name: "Orange juice", GI: 50, Terms: []string{"orange juice"}},
+	{Name: "Banana", GI: 51, Terms: []string{"banana", "saging", "plantain"}, FDCIDs: []int{173944}},
+	{Name: "Mango", GI: 51, Terms: []string{"mango", "mangga"}, FDCIDs: []int{169910}},
+	{Name: "Pineapple", GI: 59, Terms: []string{"pineapple", "pinya"}, FDCIDs: []int{169124}},
+	{Name: "Papaya", GI: 60, Terms: []string{"papaya"}, FDCIDs: []int{169926}},
+	{Name: "Watermelon", GI: 76, Terms: []string{"watermelon", "pakwan"}, FDCIDs: []int{167765}},
+	{Name: "Grapes", GI: 59, Terms: []string{"grape"}, FDCIDs: []int{174683}},
+	{Name: "Pear", GI: 38, Terms: []string{"pear"}, FDCIDs: []int{169118}},
+	{Name: "Peach", GI: 42, Terms: []string{"peach"}},
+	{Name: "Strawberries", GI: 40, Terms: []string{"strawberry", "strawberries"}, FDCIDs: []int{167762}},
+	{Name: "Dates, dried", GI: 42, Terms: []string{"date"}},
+	{Name: "Raisins", GI: 64, Terms: []string{"raisin"}},
+	{Name: "Strawberry jam", GI: 49, Terms: []string{"jam", "jelly", "marmalade"}},
+
+	// Dairy and alternatives
+	{Name: "Milk, full fat", GI: 39, Terms: []string{"milk", "whole milk"}, FDCIDs: []int{171265}},
+	{Name: "Milk, skim", GI: 37, Terms: []string{"skim milk", "skimmed milk", "low fat milk"}},
+	{Name: "Chocolate milk", GI: 43, Terms: []string{"chocolate milk"}},
+	{Name: "Yogurt, fruit", GI: 41, Terms: []string{"yogurt", "yoghurt", "frozen yogurt"}},
+	{Name: "Ice cream", GI: 51, Terms: []string{"ice cream", "gelato", "sorbetes"}},
+	{Name: "Soy milk", GI: 34, Terms: []string{"soy milk", "soya milk", "soymilk"}},
+	{Name: "Rice milk", GI: 86, Terms: []string{"rice milk"}},
+
+	// Sugars, sweets and drinks
+	{Name: "Sucrose", GI: 65, Terms: []string{"sugar", "table sugar"}, FDCIDs: []int{169655}},
+	{Name: "Glucose", GI: 103, Terms: []string{"glucose", "dextrose"}},
+	{Name: "Fructose", GI: 15, Terms: []string{"fructose"}},
+	{Name: "Honey", GI: 61, Terms: []string{"honey"}, FDCIDs: []int{169640}},
+	{Name: "Chocolate", GI: 40, Terms: []string{"chocolate"}},
+	{Name: "Soft drink", GI: 59, Terms: []string{"soda", "cola", "soft drink", "carbonated"}},
+	{Name: "Sports drink", GI: 78, Terms: []string{"sports drink", "gatorade"}},
+
+	// Mixed dishes
+	{Name: "Pizza, cheese", GI: 60, Terms: []string{"pizza"}},
+	{Name: "Hamburger bun", GI: 61, Terms: []string{"hamburger", "burger", "hot dog"}},
+	{Name: "Spring rolls", GI: 50, Terms: []string{"spring roll", "lumpia", "egg roll"}},
+}
@@ -0,0 +1,218 @@
+package glycemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExchangeGrams is the carbohydrate in one carbohydrate exchange
+// ("carb choice").
+const ExchangeGrams = 15
+
+// Categories of glycemic index and load.
+const (
+	Low    = "low"
+	Medium = "medium"
+	High   = "high"
+)
+
+// Sources of a GI match.
+const (
+	MatchedByFDCID = "fdcId"
+	MatchedByName  = "name"
+)
+
+// Carbs counts the carbohydrate in an amount of food, in grams. Net is
+// total carbohydrate less fiber and sugar alcohols, as far as reported.
+type Carbs struct {
+	Total         float64 `json:"total"`
+	Fiber         float64 `json:"fiber"`
+	SugarAlcohols float64 `json:"sugarAlcohols"`
+	Net           float64 `json:"net"`
+	Exchanges     float64 `json:"exchanges"`
+}
+
+// Count reads the carbohydrates from nutrient amounts by registry ID (see
+// nutrients.Amounts), or returns false when total carbohydrate is not
+// reported.
+func Count(amounts map[string]float64) (Carbs, bool) {
+	total, ok := amounts["carbohydrates"]
+	if !ok {
+		return Carbs{}, false
+	}
+	c := Carbs{Total: total, Fiber: amounts["fiber"], SugarAlcohols: amounts["polyols"]}
+	c.Net = math.Max(0, total-c.Fiber-c.SugarAlcohols)
+	return c.rounded(), true
+}
+
+// Scale returns the count for factor times the amount of food.
+func (c Carbs) Scale(factor float64) Carbs {
+	return Carbs{
+		Total:         c.Total * factor,
+		Fiber:         c.Fiber * factor,
+		SugarAlcohols: c.SugarAlcohols * factor,
+		Net:           c.Net * factor,
+	}.rounded()
+}
+
+// rounded rounds the grams to 0.1 and counts exchanges of net carbohydrate
+// to the nearest half.
+func (c Carbs) rounded() Carbs {
+	c.Total, c.Fiber, c.SugarAlcohols, c.Net = round(c.Total), round(c.Fiber), round(c.SugarAlcohols), round(c.Net)
+	c.Exchanges = math.Round(c.Net/ExchangeGrams*2) / 2
+	return c
+}
+
+// Reference is a food of the GI table. Terms are lowercase names matched
+// as whole words against food names; FDCIDs map USDA foods to it directly.
+type Reference struct {
+	Name   string   `json:"name"`
+	GI     int      `json:"gi"`
+	Terms  []string `json:"terms"`
+	FDCIDs []int    `json:"fdcIds,omitempty"`
+}
+
+// Match is the GI reference found for a food.
+type Match struct {
+	Reference string `json:"reference"`
+	GI        int    `json:"gi"`
+	Category  string `json:"category"`
+	MatchedBy string `json:"matchedBy"`
+}
+
+var (
+	mu      sync.RWMutex
+	byFDCID map[int]*Reference
+	terms   []term
+)
+
+type term struct {
+	pattern *regexp.Regexp
+	length  int
+	ref     *Reference
+}
+
+func init() {
+	index()
+}
+
+// index rebuilds the lookups from the table. Longer terms are tried first,
+// so "brown rice" wins over "rice".
+func index() {
+	byFDCID = map[int]*Reference{}
+	terms = nil
+	for i := range references {
+		ref := &references[i]
+		for _, id := range ref.FDCIDs {
+			byFDCID[id] = ref
+		}
+		for _, t := range ref.Terms {
+			terms = append(terms, term{
+				pattern: regexp.MustCompile(`\b` + regexp.QuoteMeta(t) + `(?:e?s)?\b`),
+				length:  len(t),
+				ref:     ref,
+			})
+		}
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return terms[i].length > terms[j].length })
+}
+
+// LoadFile adds references from a JSON array of {"name", "gi", "terms",
+// "fdcIds"}. A reference with the name of a bundled one replaces it.
+func LoadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded []Reference
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for _, ref := range loaded {
+		if ref.Name == "" || ref.GI <= 0 {
+			return fmt.Errorf("GI reference %q needs a name and a positive gi", ref.Name)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, ref := range loaded {
+		replaced := false
+		for i := range references {
+			if strings.EqualFold(references[i].Name, ref.Name) {
+				references[i], replaced = ref, true
+			}
+		}
+		if !replaced {
+			references = append(references, ref)
+		}
+	}
+	index()
+	return nil
+}
+
+// Lookup finds the GI of a food by its USDA FDC ID, then by name. Names
+// may use underscores, as classifier labels do.
+func Lookup(name string, fdcID int) (Match, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if ref, ok := byFDCID[fdcID]; ok && fdcID > 0 {
+		return match(ref, MatchedByFDCID), true
+	}
+	if ref := byName(name); ref != nil {
+		return match(ref, MatchedByName), true
+	}
+	return Match{}, false
+}
+
+func byName(name string) *Reference {
+	text := strings.ToLower(strings.ReplaceAll(name, "_", " "))
+	for _, t := range terms {
+		if t.pattern.MatchString(text) {
+			return t.ref
+		}
+	}
+	return nil
+}
+
+func match(ref *Reference, by string) Match {
+	return Match{Reference: ref.Name, GI: ref.GI, Category: Category(ref.GI), MatchedBy: by}
+}
+
+// Category classifies a glycemic index: low up to 55, high from 70.
+func Category(gi int) string {
+	switch {
+	case gi <= 55:
+		return Low
+	case gi < 70:
+		return Medium
+	}
+	return High
+}
+
+// Load is the glycemic load of an amount of food with the given net
+// carbohydrate.
+func Load(gi int, netCarbs float64) float64 {
+	return round(float64(gi) * netCarbs / 100)
+}
+
+// LoadCategory classifies a glycemic load: low up to 10, high from 20.
+func LoadCategory(gl float64) string {
+	switch {
+	case gl <= 10:
+		return Low
+	case gl < 20:
+		return Medium
+	}
+	return High
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,102 @@
+package glycemic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	c, ok := Count(map[string]float64{"carbohydrates": 64.26, "fiber": 7, "polyols": 12})
+	want := Carbs{Total: 64.3, Fiber: 7, SugarAlcohols: 12, Net: 45.3, Exchanges: 3}
+	if !ok || c != want {
+		t.Errorf("Count = %+v, want %+v", c, want)
+	}
+	if c, _ := Count(map[string]float64{"carbohydrates": 2, "fiber": 5}); c.Net != 0 {
+		t.Errorf("net carbohydrate %v, want 0 when fiber exceeds the total", c.Net)
+	}
+	if _, ok := Count(map[string]float64{"fiber": 5}); ok {
+		t.Error("Count without carbohydrates reported a count")
+	}
+}
+
+func TestScale(t *testing.T) {
+	c, _ := Count(map[string]float64{"carbohydrates": 28, "fiber": 2})
+	got := c.Scale(1.5)
+	want := Carbs{Total: 42, Fiber: 3, Net: 39, Exchanges: 2.5}
+	if got != want {
+		t.Errorf("Scale(1.5) = %+v, want %+v", got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+	}{
+		{"Brown rice, cooked", "Brown rice, boiled"},
+		{"Rice, white, cooked", "White rice, boiled"},
+		{"fried_rice", "Fried rice"},
+		{"Bananas, raw", "Banana"},
+		{"Potato chips, plain", "Potato crisps"},
+		{"french_fries", "French fries"},
+	}
+	for _, tt := range tests {
+		m, ok := Lookup(tt.name, 0)
+		if !ok || m.Reference != tt.reference || m.MatchedBy != MatchedByName {
+			t.Errorf("Lookup(%q) = %+v, want %q", tt.name, m, tt.reference)
+		}
+	}
+	if m, ok := Lookup("Beef, ground, raw", 0); ok {
+		t.Errorf("Lookup matched beef to %q", m.Reference)
+	}
+}
+
+func TestLookupFDCID(t *testing.T) {
+	m, ok := Lookup("Fish, battered, fried", 173944)
+	if !ok || m.Reference != "Banana" || m.MatchedBy != MatchedByFDCID {
+		t.Errorf("Lookup by bundled FDC ID = %+v, %v", m, ok)
+	}
+	if m, _ := Lookup("Fish, battered, fried", 0); m.Reference == "Banana" {
+		t.Error("FDC ID 0 matched a reference")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gi.json")
+	os.WriteFile(path, []byte(`[{"name": "Ube halaya", "gi": 70, "terms": ["ube halaya"], "fdcIds": [99000002]}]`), 0o644)
+	if err := LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := Lookup("", 99000002); !ok || m.Reference != "Ube halaya" || m.Category != High {
+		t.Errorf("loaded reference = %+v, %v", m, ok)
+	}
+	if m, _ := Lookup("Ube halaya jar", 0); m.GI != 70 {
+		t.Errorf("loaded term matched GI %d", m.GI)
+	}
+	if m, _ := Lookup("", 173944); m.Reference != "Banana" {
+		t.Errorf("bundled FDC ID lost after loading: %+v", m)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	os.WriteFile(bad, []byte(`[{"name": "Nothing", "gi": 0}]`), 0o644)
+	if err := LoadFile(bad); err == nil {
+		t.Error("reference without a GI accepted")
+	}
+}
+
+func TestCategories(t *testing.T) {
+	for gi, want := range map[int]string{55: Low, 56: Medium, 69: Medium, 70: High} {
+		if got := Category(gi); got != want {
+			t.Errorf("Category(%d) = %s, want %s", gi, got, want)
+		}
+	}
+	for gl, want := range map[float64]string{10: Low, 10.1: Medium, 19.9: Medium, 20: High} {
+		if got := LoadCategory(gl); got != want {
+			t.Errorf("LoadCategory(%v) = %s, want %s", gl, got, want)
+		}
+	}
+	if gl := Load(73, 45); gl != 32.9 {
+		t.Errorf("Load(73, 45) = %v, want 32.9", gl)
+	}
+}
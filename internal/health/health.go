@@ -0,0 +1,375 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Sush1sui/internal/additives"
+	"github.com/Sush1sui/internal/allergens"
+	"github.com/Sush1sui/internal/glycemic"
+	"github.com/Sush1sui/internal/nutrients"
+)
+
+// Conditions.
+const (
+	Hypertension = "hypertension"
+	Diabetes     = "diabetes"
+	CKD          = "ckd"
+	Pregnancy    = "pregnancy"
+)
+
+// Goals.
+const (
+	WeightLoss = "weight-loss"
+	LowSodium  = "low-sodium"
+	LowSugar   = "low-sugar"
+	LowSatFat  = "low-saturated-fat"
+)
+
+// Severities, lowest first.
+const (
+	Warning = "warning"
+	Danger  = "danger"
+)
+
+// CustomLimit is the reason of alerts for a nutrient only the profile's
+// limits cover.
+const CustomLimit = "custom-limit"
+
+// Bases an alert's amount refers to.
+const (
+	PerServing = "serving"
+	Per100g    = "100g"
+)
+
+// Shares of a daily limit in one serving that raise a warning and a
+// danger alert.
+const (
+	warningShare = 0.2
+	dangerShare  = 0.4
+)
+
+// Profile is a user's health conditions, goals and allergies. Limits are
+// daily limits by nutrient registry ID, in the nutrient's canonical unit;
+// they override the limits of conditions and goals and limit any other
+// nutrient on their own.
+type Profile struct {
+	Conditions []string           `json:"conditions"`
+	Goals      []string           `json:"goals"`
+	Allergies  []string           `json:"allergies"`
+	Limits     map[string]float64 `json:"limits"`
+}
+
+// limit is a daily limit a condition or goal places on a nutrient.
+type limit struct {
+	nutrient string
+	amount   float64
+}
+
+// dailyLimits are the default limits by condition and goal: AHA sodium
+// for hypertension, WHO free sugars for diabetes, KDOQI potassium,
+// phosphorus and sodium for chronic kidney disease, and caffeine and
+// preformed vitamin A upper limits in pregnancy.
+var dailyLimits = map[string][]limit{
+	Hypertension: {{"sodium", 1500}},
+	Diabetes:     {{"sugars", 25}},
+	CKD:          {{"potassium", 2000}, {"phosphorus", 800}, {"sodium", 2000}},
+	Pregnancy:    {{"caffeine", 200}, {"vitamin-a", 3000}},
+	WeightLoss:   {{"energy", 2000}},
+	LowSodium:    {{"sodium", 1500}},
+	LowSugar:     {{"sugars", 25}},
+	LowSatFat:    {{"saturated-fat", 20}},
+}
+
+var conditions = []string{Hypertension, Diabetes, CKD, Pregnancy}
+var goals = []string{WeightLoss, LowSodium, LowSugar, LowSatFat}
+
+// Check reports an error for unknown conditions, goals, allergens or
+// limit nutrients.
+func (p *Profile) Check() error {
+	for _, c := range p.Conditions {
+		if !contains(conditions, c) {
+			return fmt.Errorf("unknown condition %q (available: %s)", c, strings.Join(conditions, ", "))
+		}
+	}
+	for _, g := range p.Goals {
+		if !contains(goals, g) {
+			return fmt.Errorf("unknown goal %q (available: %s)", g, strings.Join(goals, ", "))
+		}
+	}
+	if err := allergens.CheckIDs(p.Allergies); err != nil {
+		return err
+	}
+	for id, amount := range p.Limits {
+		if _, ok := nutrients.ByID(id); !ok {
+			return fmt.Errorf("unknown nutrient %q in limits", id)
+		}
+		if amount <= 0 {
+			return fmt.Errorf("limit for %s must be positive", id)
+		}
+	}
+	return nil
+}
+
+// Input is the food alerts are computed for.
+type Input struct {
+	Name        string
+	Ingredients string
+	// Amounts are per 100 g by nutrient registry ID; see nutrients.Amounts.
+	Amounts map[string]float64
+	// ServingGrams is the serving weight, 0 when unknown; amounts are then
+	// judged per 100 g.
+	ServingGrams float64
+	// FdcID maps USDA foods to a GI reference; see glycemic.Lookup.
+	FdcID int
+}
+
+// Alert is a personalized warning. For nutrient alerts Amount is what the
+// basis provides and Limit the daily limit it is judged against.
+type Alert struct {
+	ID       string  `json:"id"`
+	Severity string  `json:"severity"`
+	Reason   string  `json:"reason"`
+	Nutrient string  `json:"nutrient,omitempty"`
+	Amount   float64 `json:"amount,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+	Limit    float64 `json:"limit,omitempty"`
+	Basis    string  `json:"basis,omitempty"`
+	Message  string  `json:"message"`
+}
+
+// Alerts computes the alerts a food raises for a profile, most severe
+// first.
+func Alerts(p Profile, in Input) []Alert {
+	alerts := []Alert{}
+	basis, factor := Per100g, 1.0
+	if in.ServingGrams > 0 {
+		basis, factor = PerServing, in.ServingGrams/100
+	}
+
+	// a nutrient limited for several reasons is judged against the
+	// strictest limit, whichever reason is listed first
+	var limited []string
+	strictest := map[string]limit{}
+	reasons := map[string]string{}
+	for _, reason := range append(append([]string(nil), p.Conditions...), p.Goals...) {
+		for _, l := range dailyLimits[reason] {
+			current, ok := strictest[l.nutrient]
+			if !ok {
+				limited = append(limited, l.nutrient)
+			}
+			if !ok || l.amount < current.amount {
+				strictest[l.nutrient], reasons[l.nutrient] = l, reason
+			}
+		}
+	}
+	var custom []string
+	for nutrient := range p.Limits {
+		if _, ok := strictest[nutrient]; !ok {
+			custom = append(custom, nutrient)
+			reasons[nutrient] = CustomLimit
+		}
+	}
+	sort.Strings(custom)
+	limited = append(limited, custom...)
+	for _, nutrient := range limited {
+		value, ok := in.Amounts[nutrient]
+		if added, has := in.Amounts["added-sugars"]; has && nutrient == "sugars" {
+			value, ok = added, true
+		}
+		if !ok {
+			continue
+		}
+		daily := strictest[nutrient].amount
+		if v, ok := p.Limits[nutrient]; ok {
+			daily = v
+		}
+		if a, ok := nutrientAlert(reasons[nutrient], nutrient, value*factor, daily, basis); ok {
+			alerts = append(alerts, a)
+		}
+	}
+	for _, c := range p.Conditions {
+		switch c {
+		case Diabetes:
+			alerts = append(alerts, glycemicAlerts(in, factor, basis)...)
+		case CKD:
+			alerts = append(alerts, phosphateAlerts(in)...)
+		case Pregnancy:
+			alerts = append(alerts, pregnancyAlerts(in)...)
+		}
+	}
+	alerts = append(alerts, allergyAlerts(p.Allergies, in)...)
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Severity == Danger && alerts[j].Severity != Danger
+	})
+	return alerts
+}
+
+func nutrientAlert(reason, nutrient string, amount, daily float64, basis string) (Alert, bool) {
+	share := amount / daily
+	severity := ""
+	switch {
+	case share >= dangerShare:
+		severity = Danger
+	case share >= warningShare:
+		severity = Warning
+	default:
+		return Alert{}, false
+	}
+	name, unit := nutrient, ""
+	if d, ok := nutrients.ByID(nutrient); ok {
+		name, unit = strings.ToLower(d.Name(nutrients.DefaultLocale)), d.Unit
+	}
+	per := "a serving"
+	if basis == Per100g {
+		per = "100 g"
+	}
+	return Alert{
+		ID:       "high-" + nutrient,
+		Severity: severity,
+		Reason:   reason,
+		Nutrient: nutrient,
+		Amount:   round(amount),
+		Unit:     unit,
+		Limit:    daily,
+		Basis:    basis,
+		Message:  fmt.Sprintf("%s has %g%s %s, %.0f%% of your daily limit", capitalize(per), round(amount), unit, name, share*100),
+	}, true
+}
+
+// glycemicAlerts flag a high or medium glycemic load.
+func glycemicAlerts(in Input, factor float64, basis string) []Alert {
+	carbs, ok := glycemic.Count(in.Amounts)
+	if !ok {
+		return nil
+	}
+	match, ok := glycemic.Lookup(in.Name, in.FdcID)
+	if !ok {
+		return nil
+	}
+	gl := glycemic.Load(match.GI, carbs.Net*factor)
+	severity := ""
+	switch glycemic.LoadCategory(gl) {
+	case glycemic.High:
+		severity = Danger
+	case glycemic.Medium:
+		severity = Warning
+	default:
+		return nil
+	}
+	return []Alert{{
+		ID:       "high-glycemic-load",
+		Severity: severity,
+		Reason:   Diabetes,
+		Amount:   gl,
+		Basis:    basis,
+		Message:  fmt.Sprintf("Estimated glycemic load of %g (GI %d, as %s)", gl, match.GI, strings.ToLower(match.Reference)),
+	}}
+}
+
+// phosphateAdditives are absorbed almost completely, unlike the phosphorus
+// in whole foods.
+var phosphateAdditives = map[string]bool{
+	"E338": true, "E339": true, "E340": true, "E341": true, "E450": true, "E451": true, "E452": true,
+}
+
+func phosphateAlerts(in Input) []Alert {
+	var alerts []Alert
+	for _, d := range additives.Detect(in.Ingredients) {
+		if phosphateAdditives[d.Code] {
+			alerts = append(alerts, Alert{
+				ID:       "phosphate-additive",
+				Severity: Warning,
+				Reason:   CKD,
+				Message:  fmt.Sprintf("Contains %s (%s), a highly absorbed phosphate additive", strings.ToLower(d.Name), d.Code),
+			})
+		}
+	}
+	return alerts
+}
+
+var (
+	alcoholWords      = regexp.MustCompile(`\b(?:alcohol|ethanol|wine|beer|rum|brandy|whisky|whiskey|vodka|liqueur|sake)\b`)
+	alcoholExclusions = regexp.MustCompile(`\b(?:wine vinegar|sugar alcohols?|alcohol[- ]free|non-alcoholic)\b`)
+	mercuryFish       = regexp.MustCompile(`\b(?:swordfish|shark|king mackerel|tilefish|marlin|bigeye tuna|orange roughy)\b`)
+	unpasteurized     = regexp.MustCompile(`\b(?:unpasteuri[sz]ed|raw milk)\b`)
+	// \b only knows ASCII letters, so "pâté" is bounded by any non-letter
+	liver = regexp.MustCompile(`(?:^|\P{L})(?:liver|pate|pâté)s?(?:$|\P{L})`)
+)
+
+// pregnancyAlerts flag alcohol, high-mercury fish, unpasteurized milk and
+// liver in the name or ingredients.
+func pregnancyAlerts(in Input) []Alert {
+	text := strings.ToLower(strings.ReplaceAll(in.Name, "_", " ") + ", " + in.Ingredients)
+	var alerts []Alert
+	add := func(id, severity, message string) {
+		alerts = append(alerts, Alert{ID: id, Severity: severity, Reason: Pregnancy, Message: message})
+	}
+	if amount := in.Amounts["alcohol"]; amount > 0 || alcoholWords.MatchString(alcoholExclusions.ReplaceAllString(text, "")) {
+		add("alcohol", Danger, "Contains alcohol, which should be avoided in pregnancy")
+	}
+	if m := mercuryFish.FindString(text); m != "" {
+		add("high-mercury-fish", Warning, fmt.Sprintf("Contains %s, a fish high in mercury", m))
+	}
+	if unpasteurized.MatchString(text) {
+		add("unpasteurized", Danger, "Contains unpasteurized milk, a listeria risk")
+	}
+	if liver.MatchString(text) {
+		add("liver", Warning, "Contains liver, very high in vitamin A")
+	}
+	return alerts
+}
+
+// allergyAlerts flag the user's allergens in the ingredients or, for
+// scanned foods without ingredients, the food name.
+func allergyAlerts(ids []string, in Input) []Alert {
+	if len(ids) == 0 {
+		return nil
+	}
+	detections := allergens.Detect(in.Ingredients)
+	detections = append(detections, allergens.Detect(strings.ReplaceAll(in.Name, "_", " "))...)
+	var alerts []Alert
+	seen := map[string]bool{}
+	for _, d := range detections {
+		if !contains(ids, d.ID) || seen[d.ID] {
+			continue
+		}
+		seen[d.ID] = true
+		terms := []string{}
+		for _, m := range d.Matches {
+			if !contains(terms, m.Term) {
+				terms = append(terms, m.Term)
+			}
+		}
+		a := Alert{ID: "allergen-" + d.ID, Severity: Danger, Reason: "allergy"}
+		if d.Status == allergens.MayContain {
+			a.Severity = Warning
+			a.Message = fmt.Sprintf("May contain %s (%s)", strings.ToLower(d.Name), strings.Join(terms, ", "))
+		} else {
+			a.Message = fmt.Sprintf("Contains %s (%s)", strings.ToLower(d.Name), strings.Join(terms, ", "))
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
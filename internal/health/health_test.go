@@ -0,0 +1,129 @@
+package health
+
+import (
+	"strings"
+	"testing"
+)
+
+// ids renders alerts as "id:severity" pairs.
+func ids(alerts []Alert) string {
+	var out []string
+	for _, a := range alerts {
+		out = append(out, a.ID+":"+a.Severity)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestCheck(t *testing.T) {
+	valid := Profile{Conditions: []string{Hypertension}, Goals: []string{LowSugar}, Allergies: []string{"peanuts"}, Limits: map[string]float64{"sodium": 1200}}
+	if err := valid.Check(); err != nil {
+		t.Errorf("valid profile: %v", err)
+	}
+	for _, p := range []Profile{
+		{Conditions: []string{"gout"}},
+		{Goals: []string{"bulking"}},
+		{Allergies: []string{"kryptonite"}},
+		{Limits: map[string]float64{"unobtainium": 1}},
+		{Limits: map[string]float64{"sodium": 0}},
+	} {
+		if err := p.Check(); err == nil {
+			t.Errorf("profile %+v accepted", p)
+		}
+	}
+}
+
+func TestNutrientAlerts(t *testing.T) {
+	salty := map[string]float64{"sodium": 800, "sugars": 4}
+	tests := []struct {
+		name string
+		p    Profile
+		in   Input
+		want string
+	}{
+		{"below the warning share", Profile{Conditions: []string{Hypertension}},
+			Input{Amounts: map[string]float64{"sodium": 200}}, ""},
+		{"per 100 g", Profile{Conditions: []string{Hypertension}},
+			Input{Amounts: salty}, "high-sodium:danger"},
+		{"per serving", Profile{Conditions: []string{Hypertension}},
+			Input{Amounts: salty, ServingGrams: 50}, "high-sodium:warning"},
+		{"custom limit", Profile{Conditions: []string{Hypertension}, Limits: map[string]float64{"sodium": 4000}},
+			Input{Amounts: salty}, "high-sodium:warning"},
+		{"limit without a condition", Profile{Limits: map[string]float64{"sodium": 1500, "potassium": 1000}},
+			Input{Amounts: map[string]float64{"sodium": 400, "potassium": 500}}, "high-potassium:danger high-sodium:warning"},
+		{"added sugars preferred", Profile{Goals: []string{LowSugar}},
+			Input{Amounts: map[string]float64{"sugars": 20, "added-sugars": 2}}, ""},
+		{"unreported", Profile{Conditions: []string{CKD}},
+			Input{Amounts: map[string]float64{"phosphorus": 100}}, ""},
+	}
+	for _, tt := range tests {
+		if got := ids(Alerts(tt.p, tt.in)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStrictestLimit(t *testing.T) {
+	p := Profile{Conditions: []string{CKD, Hypertension}, Goals: []string{LowSodium}}
+	alerts := Alerts(p, Input{Amounts: map[string]float64{"sodium": 400}})
+	if len(alerts) != 1 {
+		t.Fatalf("alerts = %+v, want one sodium alert", alerts)
+	}
+	a := alerts[0]
+	if a.Limit != 1500 || a.Reason != Hypertension || a.Severity != Warning || a.Unit != "mg" || a.Basis != Per100g {
+		t.Errorf("alert = %+v, want hypertension's 1500 mg limit", a)
+	}
+	if a.Message != "100 g has 400mg sodium, 27% of your daily limit" {
+		t.Errorf("message = %q", a.Message)
+	}
+}
+
+func TestCustomLimit(t *testing.T) {
+	alerts := Alerts(Profile{Limits: map[string]float64{"sodium": 1500}}, Input{Amounts: map[string]float64{"sodium": 700}})
+	if len(alerts) != 1 || alerts[0].Reason != CustomLimit || alerts[0].Limit != 1500 {
+		t.Errorf("alerts = %+v, want one sodium alert for the custom limit", alerts)
+	}
+}
+
+func TestConditionAlerts(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Profile
+		in   Input
+		want string
+	}{
+		{"glycemic load", Profile{Conditions: []string{Diabetes}},
+			Input{Name: "White rice, cooked", Amounts: map[string]float64{"carbohydrates": 28}, ServingGrams: 200},
+			"high-glycemic-load:danger"},
+		{"no GI reference", Profile{Conditions: []string{Diabetes}},
+			Input{Name: "Beef, raw", Amounts: map[string]float64{"carbohydrates": 28}}, ""},
+		{"phosphate additive", Profile{Conditions: []string{CKD}},
+			Input{Ingredients: "Pork, water, salt, sodium tripolyphosphate (E451)"}, "phosphate-additive:warning"},
+		{"pregnancy", Profile{Conditions: []string{Pregnancy}},
+			Input{Name: "Chicken liver pâté", Ingredients: "Chicken liver, butter, brandy"}, "alcohol:danger liver:warning"},
+		{"pregnancy exclusions", Profile{Conditions: []string{Pregnancy}},
+			Input{Name: "Salad dressing", Ingredients: "Oil, red wine vinegar, sugar alcohols, deliverance"}, ""},
+		{"pâté alone", Profile{Conditions: []string{Pregnancy}},
+			Input{Name: "Pâté"}, "liver:warning"},
+		{"mercury and raw milk", Profile{Conditions: []string{Pregnancy}},
+			Input{Ingredients: "Swordfish, raw milk cheese"}, "unpasteurized:danger high-mercury-fish:warning"},
+	}
+	for _, tt := range tests {
+		if got := ids(Alerts(tt.p, tt.in)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAllergyAlerts(t *testing.T) {
+	p := Profile{Allergies: []string{"peanuts", "milk"}}
+	alerts := Alerts(p, Input{Ingredients: "Peanuts, peanut oil, sugar. May contain milk."})
+	if got := ids(alerts); got != "allergen-peanuts:danger allergen-milk:warning" {
+		t.Fatalf("alerts = %q", got)
+	}
+	if !strings.HasPrefix(alerts[1].Message, "May contain milk") {
+		t.Errorf("message = %q", alerts[1].Message)
+	}
+	if got := ids(Alerts(p, Input{Name: "peanut_butter"})); got != "allergen-peanuts:danger" {
+		t.Errorf("name alerts = %q", got)
+	}
+}
@@ -0,0 +1,273 @@
+package ingredients
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Ingredient is one entry of an ingredient list. Start and End are
+// character (rune) offsets of the entry, sub-ingredients included, in the
+// parsed statement.
+type Ingredient struct {
+	Text string `json:"text"`
+	// Function is a class name the label gives the ingredient, as in
+	// "emulsifier: lecithins".
+	Function string `json:"function,omitempty"`
+	// Rank is the 1-based position within its list; labels order
+	// ingredients by weight.
+	Rank    int      `json:"rank"`
+	Percent *float64 `json:"percent,omitempty"`
+	// AtMost is set after a "contains 2% or less of" boundary: every
+	// following ingredient is at most that percentage.
+	AtMost      *float64     `json:"atMost,omitempty"`
+	Start       int          `json:"start"`
+	End         int          `json:"end"`
+	Ingredients []Ingredient `json:"ingredients,omitempty"`
+}
+
+// Tree is a parsed ingredient statement. Statements holds the sentences
+// after the list that are not ingredients, such as "Contains: milk." or
+// "May contain traces of nuts."
+type Tree struct {
+	Ingredients []Ingredient `json:"ingredients"`
+	Statements  []string     `json:"statements,omitempty"`
+}
+
+var (
+	listPrefix = regexp.MustCompile(`(?i)^\s*ingredients?\s*:\s*`)
+	// "contains 2% or less of", "less than 2% of", "contains less than 2% of each of the following"
+	atMostMarker = regexp.MustCompile(`(?i)^(?:and\s+)?(?:contains\s+)?(?:(?:less than|under)\s+(\d+(?:[.,]\d+)?)\s*%|(\d+(?:[.,]\d+)?)\s*%\s+or less)(?:\s+of)?(?:\s+(?:each|any|one or more))?(?:\s+of)?(?:\s+the following)?\s*:?\s*`)
+	percentText  = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*%`)
+	statement    = regexp.MustCompile(`(?i)^(?:contains|may contain|allergen|allergy|produced|manufactured|made in|made on|processed|packed|traces|warning|store|keep|puede contener|peut contenir)`)
+	eNumber      = regexp.MustCompile(`\b[eE] ?(\d{3,4}[a-z]?)\b`)
+	vitamin      = regexp.MustCompile(`\bvitamin ([a-z])(\d*)\b`)
+)
+
+// Parse parses an ingredient statement into an ordered tree. Sub-ingredients
+// come from parentheses and brackets, stated percentages are read from
+// "tomatoes (60%)", "tomatoes 60%" or "60% tomatoes", and casing is
+// normalized to sentence case.
+func Parse(text string) Tree {
+	runes := []rune(text)
+	start := 0
+	if loc := listPrefix.FindStringIndex(text); loc != nil {
+		start = len([]rune(text[:loc[1]]))
+	}
+
+	tree := Tree{Ingredients: []Ingredient{}}
+	var list []span
+	for i, sentence := range sentences(runes, start) {
+		body := strings.TrimSpace(string(runes[sentence.start:sentence.end]))
+		// "Contains 2% or less of: salt" continues the list
+		if i > 0 && statement.MatchString(body) && !atMostMarker.MatchString(body) {
+			tree.Statements = append(tree.Statements, body)
+			continue
+		}
+		list = append(list, sentence)
+	}
+	var atMost *float64
+	for _, s := range list {
+		tree.Ingredients = append(tree.Ingredients, parseList(runes, s, &atMost)...)
+	}
+	rank(tree.Ingredients)
+	return tree
+}
+
+type span struct{ start, end int }
+
+// sentences splits at full stops outside brackets that end a sentence,
+// not the decimal point of "2.5%".
+func sentences(runes []rune, start int) []span {
+	var out []span
+	depth := 0
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		case '.':
+			if depth == 0 && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+				out = append(out, span{start, i})
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(string(runes[start:])) != "" {
+		out = append(out, span{start, len(runes)})
+	}
+	return out
+}
+
+// parseList parses the comma separated entries of runes[s.start:s.end].
+// atMost carries a "2% or less" boundary from one entry to the following.
+func parseList(runes []rune, s span, atMost **float64) []Ingredient {
+	var out []Ingredient
+	depth, from := 0, s.start
+	flush := func(to int) {
+		if ing, ok := parseEntry(runes, span{from, to}, atMost); ok {
+			out = append(out, ing)
+		}
+		from = to + 1
+	}
+	for i := s.start; i < s.end; i++ {
+		switch runes[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',', ';':
+			// a comma between digits is the decimal comma of "12,5%"
+			decimal := runes[i] == ',' && i > s.start && i+1 < s.end &&
+				unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1])
+			if depth == 0 && !decimal {
+				flush(i)
+			}
+		}
+	}
+	flush(s.end)
+	return out
+}
+
+func parseEntry(runes []rune, s span, atMost **float64) (Ingredient, bool) {
+	s = trim(runes, s)
+	text := string(runes[s.start:s.end])
+	if m := atMostMarker.FindStringSubmatch(text); m != nil {
+		limit := parsePercent(m[1] + m[2])
+		*atMost = &limit
+		s.start += len([]rune(m[0]))
+		s = trim(runes, s)
+		text = string(runes[s.start:s.end])
+	}
+	var function string
+	if i := strings.Index(text, ":"); i >= 0 && !strings.ContainsAny(text[:i], "([{") {
+		function = strings.ToLower(strings.Join(strings.Fields(text[:i]), " "))
+		s.start += len([]rune(text[:i+1]))
+		s = trim(runes, s)
+		text = string(runes[s.start:s.end])
+	}
+	if text == "" {
+		return Ingredient{}, false
+	}
+
+	ing := Ingredient{Function: function, Start: s.start, End: s.end, AtMost: *atMost}
+	name := text
+	if open := indexBracket(runes, s); open >= 0 {
+		close := matchBracket(runes, open, s.end)
+		inner := span{open + 1, close}
+		name = string(runes[s.start:open])
+		if close+1 < s.end {
+			name += " " + string(runes[close+1:s.end])
+		}
+		innerText := strings.TrimSpace(string(runes[inner.start:inner.end]))
+		if p, ok := onlyPercent(innerText); ok {
+			ing.Percent = &p
+		} else {
+			var childAtMost *float64
+			ing.Ingredients = parseList(runes, inner, &childAtMost)
+			rank(ing.Ingredients)
+		}
+	}
+	if m := percentText.FindStringSubmatchIndex(name); m != nil && ing.Percent == nil {
+		p := parsePercent(name[m[2]:m[3]])
+		ing.Percent = &p
+		name = name[:m[0]] + name[m[1]:]
+	}
+	ing.Text = normalize(name)
+	if ing.Text == "" && len(ing.Ingredients) == 0 {
+		return Ingredient{}, false
+	}
+	return ing, true
+}
+
+func trim(runes []rune, s span) span {
+	for s.start < s.end && (unicode.IsSpace(runes[s.start]) || runes[s.start] == '.') {
+		s.start++
+	}
+	for s.end > s.start && (unicode.IsSpace(runes[s.end-1]) || runes[s.end-1] == '.' || runes[s.end-1] == '*') {
+		s.end--
+	}
+	return s
+}
+
+func indexBracket(runes []rune, s span) int {
+	for i := s.start; i < s.end; i++ {
+		if runes[i] == '(' || runes[i] == '[' || runes[i] == '{' {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchBracket finds the bracket closing the one at open, or end when the
+// statement never closes it.
+func matchBracket(runes []rune, open, end int) int {
+	depth := 0
+	for i := open; i < end; i++ {
+		switch runes[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+func onlyPercent(s string) (float64, bool) {
+	m := percentText.FindStringSubmatchIndex(s)
+	if m == nil || strings.TrimSpace(s[:m[0]]+s[m[1]:]) != "" {
+		return 0, false
+	}
+	return parsePercent(s[m[2]:m[3]]), true
+}
+
+func parsePercent(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return v
+}
+
+func rank(list []Ingredient) {
+	for i := range list {
+		list[i].Rank = i + 1
+	}
+}
+
+// normalize puts an ingredient name in sentence case, keeping E-numbers
+// and vitamin letters upper case.
+func normalize(name string) string {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	name = strings.Trim(name, " -–:*")
+	name = eNumber.ReplaceAllStringFunc(name, func(m string) string {
+		return "E" + strings.ToLower(strings.TrimLeft(m[1:], " "))
+	})
+	name = vitamin.ReplaceAllStringFunc(name, func(m string) string {
+		return "vitamin " + strings.ToUpper(m[len("vitamin "):])
+	})
+	for i, r := range name {
+		return string(unicode.ToUpper(r)) + name[i+len(string(r)):]
+	}
+	return name
+}
+
+// Walk calls fn for every ingredient of a tree, parents before their
+// sub-ingredients, with the depth of nesting (0 for the top level).
+func Walk(list []Ingredient, fn func(ing Ingredient, depth int)) {
+	var walk func([]Ingredient, int)
+	walk = func(list []Ingredient, depth int) {
+		for _, ing := range list {
+			fn(ing, depth)
+			walk(ing.Ingredients, depth+1)
+		}
+	}
+	walk(list, 0)
+}
@@ -0,0 +1,74 @@
+package ingredients
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// render writes a list as "Text", with "@rank", "=percent", "<=atMost",
+// "fn:" and "[children]" where set.
+func render(list []Ingredient) string {
+	var out []string
+	for _, ing := range list {
+		s := ing.Text
+		if ing.Function != "" {
+			s = ing.Function + ":" + s
+		}
+		if ing.Percent != nil {
+			s += fmt.Sprintf("=%v", *ing.Percent)
+		}
+		if ing.AtMost != nil {
+			s += fmt.Sprintf("<=%v", *ing.AtMost)
+		}
+		if len(ing.Ingredients) > 0 {
+			s += "[" + render(ing.Ingredients) + "]"
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, ", ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text       string
+		want       string
+		statements []string
+	}{
+		{"Ingredients: Water, SUGAR, salt.", "Water, Sugar, Salt", nil},
+		{"Tomatoes (60%), onion 12,5%, 5% garlic, basil", "Tomatoes=60, Onion=12.5, Garlic=5, Basil", nil},
+		{"Milk chocolate (sugar, cocoa butter [from fair trade cocoa]), hazelnuts 13%",
+			"Milk chocolate[Sugar, Cocoa butter[From fair trade cocoa]], Hazelnuts=13", nil},
+		{"Flour, emulsifier: soy lecithin, preservative: e 202, vitamin b12",
+			"Flour, emulsifier:Soy lecithin, preservative:E202, Vitamin B12", nil},
+		{"Water, sugar, contains 2% or less of: salt, citric acid",
+			"Water, Sugar, Salt<=2, Citric acid<=2", nil},
+		{"Water, sugar. Contains 2% or less of salt, yeast. Contains: wheat. May contain traces of nuts.",
+			"Water, Sugar, Salt<=2, Yeast<=2", []string{"Contains: wheat", "May contain traces of nuts"}},
+		{"Oats, dried fruit 2.5% (raisins, dates)", "Oats, Dried fruit=2.5[Raisins, Dates]", nil},
+		{"", "", nil},
+	}
+	for _, tt := range tests {
+		tree := Parse(tt.text)
+		if got := render(tree.Ingredients); got != tt.want {
+			t.Errorf("Parse(%q) = %s\nwant %s", tt.text, got, tt.want)
+		}
+		if fmt.Sprint(tree.Statements) != fmt.Sprint(tt.statements) {
+			t.Errorf("Parse(%q) statements = %q, want %q", tt.text, tree.Statements, tt.statements)
+		}
+	}
+}
+
+func TestParseOffsets(t *testing.T) {
+	text := "Ingredients: Crème (lait), sucre"
+	tree := Parse(text)
+	runes := []rune(text)
+	var spans []string
+	Walk(tree.Ingredients, func(ing Ingredient, depth int) {
+		spans = append(spans, fmt.Sprintf("%d:%s@%d", depth, string(runes[ing.Start:ing.End]), ing.Rank))
+	})
+	want := "[0:Crème (lait)@1 1:lait@1 0:sucre@2]"
+	if fmt.Sprint(spans) != want {
+		t.Errorf("spans = %v, want %s", spans, want)
+	}
+}
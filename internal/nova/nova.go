@@ -0,0 +1,180 @@
+package nova
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Sush1sui/internal/additives"
+	"github.com/Sush1sui/internal/ingredients"
+)
+
+// Groups of the NOVA classification.
+const (
+	Unprocessed    = 1
+	Culinary       = 2
+	Processed      = 3
+	UltraProcessed = 4
+)
+
+// Sources of a classification.
+const (
+	SourceOpenFoodFacts = "openfoodfacts"
+	SourceEstimate      = "estimate"
+)
+
+var groupNames = map[int]string{
+	Unprocessed:    "Unprocessed or minimally processed foods",
+	Culinary:       "Processed culinary ingredients",
+	Processed:      "Processed foods",
+	UltraProcessed: "Ultra-processed food and drink products",
+}
+
+// Marker is an ingredient or additive that places a food in a group.
+type Marker struct {
+	Group int    `json:"group"`
+	Kind  string `json:"kind"`
+	// Marker is what the ingredient is, e.g. "flavoring" or an additive
+	// class such as "emulsifier".
+	Marker string `json:"marker"`
+	Text   string `json:"text"`
+}
+
+// Result is a NOVA group with where it came from and, for estimates, the
+// markers behind it.
+type Result struct {
+	Group   int      `json:"group"`
+	Name    string   `json:"name"`
+	Source  string   `json:"source"`
+	Markers []Marker `json:"markers"`
+}
+
+// Marker kinds.
+const (
+	KindIngredient = "ingredient"
+	KindAdditive   = "additive"
+)
+
+// ultraProcessedWords are ingredients of industrial use only: sugars and
+// proteins refined from whole foods, modified fats and flavorings.
+var ultraProcessedWords = map[string]string{
+	"flavor": "flavoring", "flavour": "flavoring", "flavoring": "flavoring", "flavouring": "flavoring",
+	"natural flavor": "flavoring", "artificial flavor": "flavoring", "aroma": "flavoring",
+	"maltodextrin": "refined sugar", "dextrose": "refined sugar", "fructose": "refined sugar",
+	"glucose syrup": "refined sugar", "glucose-fructose syrup": "refined sugar",
+	"fructose syrup": "refined sugar", "corn syrup": "refined sugar",
+	"high fructose corn syrup": "refined sugar", "invert sugar": "refined sugar",
+	"polydextrose": "refined sugar",
+	"hydrogenated": "modified fat", "partially hydrogenated": "modified fat", "interesterified": "modified fat",
+	"hydrolyzed protein": "refined protein", "hydrolysed protein": "refined protein",
+	"protein isolate": "refined protein", "soy protein isolate": "refined protein",
+	"whey protein": "refined protein", "milk protein concentrate": "refined protein",
+	"casein": "refined protein", "caseinate": "refined protein", "sodium caseinate": "refined protein",
+	"textured vegetable protein": "refined protein", "vital wheat gluten": "refined protein",
+	"lactose": "refined sugar", "whey powder": "refined protein",
+	"modified starch": "modified starch", "modified corn starch": "modified starch",
+	"modified food starch": "modified starch", "mechanically separated": "reconstituted meat",
+}
+
+// culinaryWords are processed culinary ingredients: on their own a food is
+// group 2, added to foods they make it group 3.
+var culinaryWords = map[string]string{
+	"sugar": "sugar", "cane sugar": "sugar", "brown sugar": "sugar", "molasses": "sugar",
+	"honey": "sugar", "maple syrup": "sugar",
+	"salt": "salt", "sea salt": "salt", "iodized salt": "salt",
+	"oil": "oil or fat", "butter": "oil or fat", "lard": "oil or fat", "ghee": "oil or fat",
+	"shortening": "oil or fat", "vinegar": "vinegar",
+	"starch": "starch", "corn starch": "starch", "cornstarch": "starch",
+}
+
+// ultraProcessedClasses are additive classes used to make food
+// palatable or stable rather than to preserve it.
+var ultraProcessedClasses = map[string]bool{
+	additives.Emulsifier:     true,
+	additives.Colorant:       true,
+	additives.FlavorEnhancer: true,
+	additives.Sweetener:      true,
+	additives.GlazingAgent:   true,
+	additives.Thickener:      true,
+	additives.Stabilizer:     true,
+	additives.Humectant:      true,
+}
+
+var (
+	ultraProcessed = wordPattern(ultraProcessedWords)
+	culinary       = wordPattern(culinaryWords)
+)
+
+func wordPattern(words map[string]string) *regexp.Regexp {
+	list := make([]string, 0, len(words))
+	for w := range words {
+		list = append(list, regexp.QuoteMeta(w))
+	}
+	sort.Slice(list, func(i, j int) bool { return len(list[i]) > len(list[j]) })
+	return regexp.MustCompile(`\b(?:` + strings.Join(list, "|") + `)s?\b`)
+}
+
+// FromGroup returns a group reported by Open Food Facts, or false when it
+// is not a NOVA group.
+func FromGroup(group int) (Result, bool) {
+	name, ok := groupNames[group]
+	if !ok {
+		return Result{}, false
+	}
+	return Result{Group: group, Name: name, Source: SourceOpenFoodFacts, Markers: []Marker{}}, true
+}
+
+// Estimate classifies a food from its ingredient statement, or returns
+// false when there is none. Ultra-processed ingredients and additives make
+// it group 4; otherwise culinary ingredients make it group 3, or group 2
+// when the food is a culinary ingredient alone; otherwise group 1.
+func Estimate(statement string) (Result, bool) {
+	tree := ingredients.Parse(statement)
+	if len(tree.Ingredients) == 0 {
+		return Result{}, false
+	}
+
+	markers := []Marker{}
+	seen := map[string]bool{}
+	add := func(m Marker) {
+		key := m.Marker + "|" + strings.ToLower(m.Text)
+		if !seen[key] {
+			seen[key] = true
+			markers = append(markers, m)
+		}
+	}
+	ingredients.Walk(tree.Ingredients, func(ing ingredients.Ingredient, _ int) {
+		text := strings.ToLower(ing.Text)
+		for _, w := range ultraProcessed.FindAllString(text, -1) {
+			add(Marker{UltraProcessed, KindIngredient, lookup(ultraProcessedWords, w), ing.Text})
+		}
+		for _, w := range culinary.FindAllString(text, -1) {
+			add(Marker{Processed, KindIngredient, lookup(culinaryWords, w), ing.Text})
+		}
+	})
+	for _, d := range additives.Detect(statement) {
+		if ultraProcessedClasses[d.Class] {
+			add(Marker{UltraProcessed, KindAdditive, d.Class, d.Matches[0].Text})
+		}
+	}
+
+	group := Unprocessed
+	for _, m := range markers {
+		group = max(group, m.Group)
+	}
+	if group == Processed && len(tree.Ingredients) == 1 && len(tree.Ingredients[0].Ingredients) == 0 {
+		group = Culinary
+		for i := range markers {
+			markers[i].Group = Culinary
+		}
+	}
+	sort.SliceStable(markers, func(i, j int) bool { return markers[i].Group > markers[j].Group })
+	return Result{Group: group, Name: groupNames[group], Source: SourceEstimate, Markers: markers}, true
+}
+
+func lookup(words map[string]string, w string) string {
+	if m, ok := words[w]; ok {
+		return m
+	}
+	return words[strings.TrimSuffix(w, "s")]
+}
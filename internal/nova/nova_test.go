@@ -0,0 +1,70 @@
+package nova
+
+import "testing"
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		statement string
+		group     int
+	}{
+		{"Rolled oats", Unprocessed},
+		{"Chickpeas, water", Unprocessed},
+		{"Extra virgin olive oil", Culinary},
+		{"Sea salt", Culinary},
+		{"Chickpeas, water, salt", Processed},
+		{"Wheat flour, water, sugar, sunflower oil, yeast", Processed},
+		{"Water, sugar, natural flavors", UltraProcessed},
+		{"Sugar, cocoa butter, milk powder, emulsifier (soy lecithin)", UltraProcessed},
+		{"Corn, palm oil, maltodextrin, salt", UltraProcessed},
+		{"Water, sweetener: sucralose", UltraProcessed},
+	}
+	for _, tt := range tests {
+		r, ok := Estimate(tt.statement)
+		if !ok {
+			t.Errorf("Estimate(%q) found no ingredients", tt.statement)
+			continue
+		}
+		if r.Group != tt.group || r.Source != SourceEstimate || r.Name != groupNames[tt.group] {
+			t.Errorf("Estimate(%q) = group %d %q, want %d", tt.statement, r.Group, r.Source, tt.group)
+		}
+	}
+	if _, ok := Estimate(""); ok {
+		t.Error("Estimate(\"\") classified an empty statement")
+	}
+}
+
+func TestEstimateMarkers(t *testing.T) {
+	r, _ := Estimate("Sugar, flavourings, salt, glucose syrup, emulsifier: E322, flavourings")
+	want := []Marker{
+		{UltraProcessed, KindIngredient, "flavoring", "Flavourings"},
+		{UltraProcessed, KindIngredient, "refined sugar", "Glucose syrup"},
+		{UltraProcessed, KindAdditive, "emulsifier", "E322"},
+		{Processed, KindIngredient, "sugar", "Sugar"},
+		{Processed, KindIngredient, "salt", "Salt"},
+	}
+	if len(r.Markers) != len(want) {
+		t.Fatalf("markers = %+v, want %+v", r.Markers, want)
+	}
+	for i := range want {
+		if r.Markers[i] != want[i] {
+			t.Errorf("marker %d = %+v, want %+v", i, r.Markers[i], want[i])
+		}
+	}
+
+	r, _ = Estimate("Butter")
+	if len(r.Markers) != 1 || r.Markers[0].Group != Culinary {
+		t.Errorf("culinary ingredient markers = %+v", r.Markers)
+	}
+}
+
+func TestFromGroup(t *testing.T) {
+	r, ok := FromGroup(UltraProcessed)
+	if !ok || r.Source != SourceOpenFoodFacts || r.Name != groupNames[UltraProcessed] || r.Markers == nil {
+		t.Errorf("FromGroup(4) = %+v, %v", r, ok)
+	}
+	for _, g := range []int{0, 5} {
+		if _, ok := FromGroup(g); ok {
+			t.Errorf("FromGroup(%d) accepted", g)
+		}
+	}
+}
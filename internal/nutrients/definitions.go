@@ -0,0 +1,290 @@
+package nutrients
+
+// definitions is the nutrient registry. Display names are given in English
+// ("en"), Filipino ("fil") and Spanish ("es"); USDA nutrient numbers and
+// Nutritionix attr_ids are those of FoodData Central's SR Legacy numbering.
+var definitions = []Definition{
+	{
+		ID:      "energy",
+		Names:   map[string]string{"en": "Energy", "fil": "Enerhiya", "es": "Energía"},
+		Unit:    "kcal",
+		Codes:   []Code{{"208", 208, "kcal"}, {"268", 268, "kJ"}, {"957", 0, "kcal"}, {"958", 0, "kcal"}},
+		OFFKey:  "energy-kcal",
+		Aliases: []string{"energy kcal", "calories", "energy (atwater general factors)", "energy (atwater specific factors)"},
+	},
+	{
+		ID:      "protein",
+		Names:   map[string]string{"en": "Protein", "fil": "Protina", "es": "Proteínas"},
+		Unit:    "g",
+		Codes:   []Code{{"203", 203, "g"}},
+		OFFKey:  "proteins",
+		Aliases: []string{"proteins"},
+	},
+	{
+		ID:      "fat",
+		Names:   map[string]string{"en": "Total Fat", "fil": "Kabuuang Taba", "es": "Grasas totales"},
+		Unit:    "g",
+		Codes:   []Code{{"204", 204, "g"}},
+		OFFKey:  "fat",
+		Aliases: []string{"total lipid (fat)", "fat", "total fat", "lipids"},
+	},
+	{
+		ID:      "saturated-fat",
+		Names:   map[string]string{"en": "Saturated Fats", "fil": "Saturated na Taba", "es": "Grasas saturadas"},
+		Unit:    "g",
+		Codes:   []Code{{"606", 606, "g"}},
+		OFFKey:  "saturated-fat",
+		Aliases: []string{"fatty acids, total saturated", "saturated fat"},
+	},
+	{
+		ID:      "trans-fat",
+		Names:   map[string]string{"en": "Trans Fats", "fil": "Trans na Taba", "es": "Grasas trans"},
+		Unit:    "g",
+		Codes:   []Code{{"605", 605, "g"}},
+		OFFKey:  "trans-fat",
+		Aliases: []string{"fatty acids, total trans", "trans fat"},
+	},
+	{
+		ID:      "monounsaturated-fat",
+		Names:   map[string]string{"en": "Monounsaturated Fats", "fil": "Monounsaturated na Taba", "es": "Grasas monoinsaturadas"},
+		Unit:    "g",
+		Codes:   []Code{{"645", 645, "g"}},
+		OFFKey:  "monounsaturated-fat",
+		Aliases: []string{"fatty acids, total monounsaturated", "monounsaturated fat"},
+	},
+	{
+		ID:      "polyunsaturated-fat",
+		Names:   map[string]string{"en": "Polyunsaturated Fats", "fil": "Polyunsaturated na Taba", "es": "Grasas poliinsaturadas"},
+		Unit:    "g",
+		Codes:   []Code{{"646", 646, "g"}},
+		OFFKey:  "polyunsaturated-fat",
+		Aliases: []string{"fatty acids, total polyunsaturated", "polyunsaturated fat"},
+	},
+	{
+		ID:     "cholesterol",
+		Names:  map[string]string{"en": "Cholesterol", "fil": "Kolesterol", "es": "Colesterol"},
+		Unit:   "mg",
+		Codes:  []Code{{"601", 601, "mg"}},
+		OFFKey: "cholesterol",
+	},
+	{
+		ID:      "carbohydrates",
+		Names:   map[string]string{"en": "Carbohydrates", "fil": "Karbohidrat", "es": "Carbohidratos"},
+		Unit:    "g",
+		Codes:   []Code{{"205", 205, "g"}},
+		OFFKey:  "carbohydrates",
+		Aliases: []string{"carbohydrate, by difference", "carbohydrate", "total carbohydrate"},
+	},
+	{
+		ID:      "sugars",
+		Names:   map[string]string{"en": "Sugar", "fil": "Asukal", "es": "Azúcares"},
+		Unit:    "g",
+		Codes:   []Code{{"269", 269, "g"}, {"2000", 0, "g"}},
+		OFFKey:  "sugars",
+		Aliases: []string{"total sugars", "sugars, total including nlea", "sugars, total", "sugars"},
+	},
+	{
+		ID:      "added-sugars",
+		Names:   map[string]string{"en": "Added Sugars", "fil": "Idinagdag na Asukal", "es": "Azúcares añadidos"},
+		Unit:    "g",
+		Codes:   []Code{{"539", 539, "g"}},
+		OFFKey:  "added-sugars",
+		Aliases: []string{"sugars, added", "added sugar"},
+	},
+	{
+		ID:      "polyols",
+		Names:   map[string]string{"en": "Sugar Alcohols", "fil": "Sugar Alcohol", "es": "Polialcoholes"},
+		Unit:    "g",
+		Codes:   []Code{{"299", 299, "g"}},
+		OFFKey:  "polyols",
+		Aliases: []string{"sugar alcohol", "sugars, alcohols"},
+	},
+	{
+		ID:      "fiber",
+		Names:   map[string]string{"en": "Dietary Fiber", "fil": "Dietary Fiber", "es": "Fibra alimentaria"},
+		Unit:    "g",
+		Codes:   []Code{{"291", 291, "g"}},
+		OFFKey:  "fiber",
+		Aliases: []string{"fiber, total dietary", "fibre", "fiber", "dietary fibre"},
+	},
+	{
+		ID:      "sodium",
+		Names:   map[string]string{"en": "Sodium", "fil": "Sodyum", "es": "Sodio"},
+		Unit:    "mg",
+		Codes:   []Code{{"307", 307, "mg"}},
+		OFFKey:  "sodium",
+		Aliases: []string{"sodium, na"},
+	},
+	{
+		ID:     "salt",
+		Names:  map[string]string{"en": "Salt", "fil": "Asin", "es": "Sal"},
+		Unit:   "g",
+		OFFKey: "salt",
+	},
+	{
+		ID:      "potassium",
+		Names:   map[string]string{"en": "Potassium", "fil": "Potasyum", "es": "Potasio"},
+		Unit:    "mg",
+		Codes:   []Code{{"306", 306, "mg"}},
+		OFFKey:  "potassium",
+		Aliases: []string{"potassium, k"},
+	},
+	{
+		ID:      "calcium",
+		Names:   map[string]string{"en": "Calcium", "fil": "Kalsiyum", "es": "Calcio"},
+		Unit:    "mg",
+		Codes:   []Code{{"301", 301, "mg"}},
+		OFFKey:  "calcium",
+		Aliases: []string{"calcium, ca"},
+	},
+	{
+		ID:      "iron",
+		Names:   map[string]string{"en": "Iron", "fil": "Iron", "es": "Hierro"},
+		Unit:    "mg",
+		Codes:   []Code{{"303", 303, "mg"}},
+		OFFKey:  "iron",
+		Aliases: []string{"iron, fe"},
+	},
+	{
+		ID:      "magnesium",
+		Names:   map[string]string{"en": "Magnesium", "fil": "Magnesyum", "es": "Magnesio"},
+		Unit:    "mg",
+		Codes:   []Code{{"304", 304, "mg"}},
+		OFFKey:  "magnesium",
+		Aliases: []string{"magnesium, mg"},
+	},
+	{
+		ID:      "phosphorus",
+		Names:   map[string]string{"en": "Phosphorus", "fil": "Posporo", "es": "Fósforo"},
+		Unit:    "mg",
+		Codes:   []Code{{"305", 305, "mg"}},
+		OFFKey:  "phosphorus",
+		Aliases: []string{"phosphorus, p"},
+	},
+	{
+		ID:      "zinc",
+		Names:   map[string]string{"en": "Zinc", "fil": "Zinc", "es": "Zinc"},
+		Unit:    "mg",
+		Codes:   []Code{{"309", 309, "mg"}},
+		OFFKey:  "zinc",
+		Aliases: []string{"zinc, zn"},
+	},
+	{
+		ID:      "vitamin-a",
+		Names:   map[string]string{"en": "Vitamin A", "fil": "Bitamina A", "es": "Vitamina A"},
+		Unit:    "µg",
+		Codes:   []Code{{"320", 320, "µg"}},
+		OFFKey:  "vitamin-a",
+		Aliases: []string{"vitamin a, rae"},
+	},
+	{
+		// IU cannot be converted to RAE without knowing how much of the
+		// vitamin A is retinol, so it has no daily value
+		ID:      "vitamin-a-iu",
+		Names:   map[string]string{"en": "Vitamin A (IU)", "fil": "Bitamina A (IU)", "es": "Vitamina A (UI)"},
+		Unit:    "IU",
+		Codes:   []Code{{"318", 318, "IU"}},
+		Aliases: []string{"vitamin a, iu"},
+	},
+	{
+		ID:      "vitamin-c",
+		Names:   map[string]string{"en": "Vitamin C", "fil": "Bitamina C", "es": "Vitamina C"},
+		Unit:    "mg",
+		Codes:   []Code{{"401", 401, "mg"}},
+		OFFKey:  "vitamin-c",
+		Aliases: []string{"vitamin c, total ascorbic acid"},
+	},
+	{
+		ID:      "vitamin-d",
+		Names:   map[string]string{"en": "Vitamin D", "fil": "Bitamina D", "es": "Vitamina D"},
+		Unit:    "µg",
+		Codes:   []Code{{"328", 328, "µg"}, {"324", 324, "IU"}},
+		OFFKey:  "vitamin-d",
+		Aliases: []string{"vitamin d (d2 + d3)", "vitamin d (d2 + d3), international units", "vitamin d2 + d3"},
+	},
+	{
+		ID:      "vitamin-e",
+		Names:   map[string]string{"en": "Vitamin E", "fil": "Bitamina E", "es": "Vitamina E"},
+		Unit:    "mg",
+		Codes:   []Code{{"323", 323, "mg"}},
+		OFFKey:  "vitamin-e",
+		Aliases: []string{"vitamin e (alpha-tocopherol)"},
+	},
+	{
+		ID:      "vitamin-k",
+		Names:   map[string]string{"en": "Vitamin K", "fil": "Bitamina K", "es": "Vitamina K"},
+		Unit:    "µg",
+		Codes:   []Code{{"430", 430, "µg"}},
+		OFFKey:  "vitamin-k",
+		Aliases: []string{"vitamin k (phylloquinone)"},
+	},
+	{
+		ID:      "thiamin",
+		Names:   map[string]string{"en": "Thiamin", "fil": "Thiamin", "es": "Tiamina"},
+		Unit:    "mg",
+		Codes:   []Code{{"404", 404, "mg"}},
+		OFFKey:  "vitamin-b1",
+		Aliases: []string{"vitamin b1", "thiamine"},
+	},
+	{
+		ID:      "riboflavin",
+		Names:   map[string]string{"en": "Riboflavin", "fil": "Riboflavin", "es": "Riboflavina"},
+		Unit:    "mg",
+		Codes:   []Code{{"405", 405, "mg"}},
+		OFFKey:  "vitamin-b2",
+		Aliases: []string{"vitamin b2"},
+	},
+	{
+		ID:      "niacin",
+		Names:   map[string]string{"en": "Niacin", "fil": "Niacin", "es": "Niacina"},
+		Unit:    "mg",
+		Codes:   []Code{{"406", 406, "mg"}},
+		OFFKey:  "vitamin-pp",
+		Aliases: []string{"vitamin b3"},
+	},
+	{
+		ID:      "vitamin-b6",
+		Names:   map[string]string{"en": "Vitamin B6", "fil": "Bitamina B6", "es": "Vitamina B6"},
+		Unit:    "mg",
+		Codes:   []Code{{"415", 415, "mg"}},
+		OFFKey:  "vitamin-b6",
+		Aliases: []string{"vitamin b-6"},
+	},
+	{
+		ID:      "folate",
+		Names:   map[string]string{"en": "Folate", "fil": "Folate", "es": "Folato"},
+		Unit:    "µg",
+		Codes:   []Code{{"417", 417, "µg"}, {"435", 435, "µg"}},
+		OFFKey:  "vitamin-b9",
+		Aliases: []string{"folate, total", "folate, dfe", "vitamin b9", "folic acid"},
+	},
+	{
+		ID:      "vitamin-b12",
+		Names:   map[string]string{"en": "Vitamin B12", "fil": "Bitamina B12", "es": "Vitamina B12"},
+		Unit:    "µg",
+		Codes:   []Code{{"418", 418, "µg"}},
+		OFFKey:  "vitamin-b12",
+		Aliases: []string{"vitamin b-12"},
+	},
+	{
+		ID:     "caffeine",
+		Names:  map[string]string{"en": "Caffeine", "fil": "Caffeine", "es": "Cafeína"},
+		Unit:   "mg",
+		Codes:  []Code{{"262", 262, "mg"}},
+		OFFKey: "caffeine",
+	},
+	{
+		ID:      "alcohol",
+		Names:   map[string]string{"en": "Alcohol", "fil": "Alkohol", "es": "Alcohol"},
+		Unit:    "g",
+		Codes:   []Code{{"221", 221, "g"}},
+		OFFKey:  "alcohol",
+		Aliases: []string{"alcohol, ethyl"},
+	},
+	{
+		ID:    "water",
+		Names: map[string]string{"en": "Water", "fil": "Tubig", "es": "Agua"},
+		Unit:  "g",
+		Codes: []Code{{"255", 255, "g"}},
+	},
+}
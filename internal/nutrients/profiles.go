@@ -0,0 +1,229 @@
+package nutrients
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Zero-value policies: whether nutrients reported as 0 are shown.
+const (
+	ZerosKeep = "keep"
+	ZerosOmit = "omit"
+)
+
+// Missing-value policies: whether profile nutrients the source did not
+// report are shown, with a null amount, or left out.
+const (
+	MissingOmit = "omit"
+	MissingNull = "null"
+)
+
+// Profile decides which nutrients a response lists and in what order.
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Nutrients are registry IDs in display order. An empty list shows every
+	// reported nutrient, registry ones first in registry order.
+	Nutrients []string `json:"nutrients,omitempty"`
+	Zeros     string   `json:"zeros,omitempty"`
+	Missing   string   `json:"missing,omitempty"`
+}
+
+// DefaultProfile is used when a request names none.
+const DefaultProfile = "full"
+
+var (
+	profileMu sync.RWMutex
+	profiles  = map[string]*Profile{
+		"full": {
+			Name:        "full",
+			Description: "Every nutrient the source reports",
+			Zeros:       ZerosOmit,
+			Missing:     MissingOmit,
+		},
+		"label-basic": {
+			Name:        "label-basic",
+			Description: "The nutrients of a US Nutrition Facts label",
+			Nutrients: []string{
+				"energy", "fat", "saturated-fat", "trans-fat", "cholesterol", "sodium",
+				"carbohydrates", "fiber", "sugars", "added-sugars", "protein",
+				"vitamin-d", "calcium", "iron", "potassium",
+			},
+			Zeros:   ZerosKeep,
+			Missing: MissingNull,
+		},
+		"eu-label": {
+			Name:        "eu-label",
+			Description: "The mandatory EU nutrition declaration",
+			Nutrients:   []string{"energy", "fat", "saturated-fat", "carbohydrates", "sugars", "protein", "salt"},
+			Zeros:       ZerosKeep,
+			Missing:     MissingNull,
+		},
+		"micronutrients": {
+			Name:        "micronutrients",
+			Description: "Vitamins and minerals",
+			Nutrients: []string{
+				"vitamin-a", "vitamin-c", "vitamin-d", "vitamin-e", "vitamin-k",
+				"thiamin", "riboflavin", "niacin", "vitamin-b6", "folate", "vitamin-b12",
+				"calcium", "iron", "magnesium", "phosphorus", "potassium", "sodium", "zinc",
+			},
+			Zeros:   ZerosKeep,
+			Missing: MissingOmit,
+		},
+		"diabetic": {
+			Name:        "diabetic",
+			Description: "Carbohydrate detail for blood sugar management",
+			Nutrients: []string{
+				"energy", "carbohydrates", "fiber", "sugars", "added-sugars", "polyols",
+				"protein", "fat", "saturated-fat", "sodium",
+			},
+			Zeros:   ZerosKeep,
+			Missing: MissingNull,
+		},
+	}
+)
+
+// LoadProfiles adds or replaces profiles from a JSON file of the form
+// {"<name>": {"description": ..., "nutrients": [...], "zeros": ..., "missing": ...}}.
+func LoadProfiles(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded map[string]*Profile
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for name, p := range loaded {
+		p.Name = name
+		for _, id := range p.Nutrients {
+			if _, ok := byID[id]; !ok {
+				return fmt.Errorf("profile %q: unknown nutrient %q", name, id)
+			}
+		}
+		if err := checkPolicies(p.Zeros, p.Missing); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	for name, p := range loaded {
+		profiles[name] = p
+	}
+	return nil
+}
+
+// SelectProfile returns a profile by name, defaulting to DefaultProfile.
+// Non-empty zeros and missing override the profile's own policies.
+func SelectProfile(name, zeros, missing string) (Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	profileMu.RLock()
+	p, ok := profiles[name]
+	names := make([]string, 0, len(profiles))
+	for n := range profiles {
+		names = append(names, n)
+	}
+	profileMu.RUnlock()
+	if !ok {
+		sort.Strings(names)
+		return Profile{}, fmt.Errorf("unknown nutrient profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	if err := checkPolicies(zeros, missing); err != nil {
+		return Profile{}, err
+	}
+
+	selected := *p
+	if zeros != "" {
+		selected.Zeros = zeros
+	}
+	if missing != "" {
+		selected.Missing = missing
+	}
+	return selected, nil
+}
+
+func checkPolicies(zeros, missing string) error {
+	if zeros != "" && zeros != ZerosKeep && zeros != ZerosOmit {
+		return fmt.Errorf("zeros must be %q or %q", ZerosKeep, ZerosOmit)
+	}
+	if missing != "" && missing != MissingOmit && missing != MissingNull {
+		return fmt.Errorf("missing must be %q or %q", MissingOmit, MissingNull)
+	}
+	return nil
+}
+
+// Apply selects and orders the nutrients of a name/amount/unit list. A
+// reported 0 stays 0, while a profile nutrient the source did not report is
+// listed with a null amount under MissingNull. Entries are copied, so the
+// list can be a cached product's nutrition.
+func (p Profile) Apply(list []map[string]interface{}) []map[string]interface{} {
+	byNutrient := map[string]map[string]interface{}{}
+	var unknown []map[string]interface{}
+	for _, n := range list {
+		if p.Zeros == ZerosOmit {
+			if amount, ok := n["amount"].(float64); ok && amount == 0 {
+				continue
+			}
+		}
+		id, _ := n["id"].(string)
+		if _, ok := byID[id]; !ok {
+			unknown = append(unknown, n)
+			continue
+		}
+		if _, seen := byNutrient[id]; !seen {
+			byNutrient[id] = n
+		}
+	}
+
+	ids := p.Nutrients
+	if len(ids) == 0 {
+		for _, d := range definitions {
+			ids = append(ids, d.ID)
+		}
+	}
+	var basis interface{}
+	if len(list) > 0 {
+		basis = list[0]["basis"]
+	}
+	out := []map[string]interface{}{}
+	for _, id := range ids {
+		if n, ok := byNutrient[id]; ok {
+			out = append(out, copyEntry(n))
+			continue
+		}
+		if p.Missing == MissingNull && len(p.Nutrients) > 0 {
+			d := byID[id]
+			missing := map[string]interface{}{
+				"id":     d.ID,
+				"name":   d.Name(DefaultLocale),
+				"amount": nil,
+				"unit":   d.Unit,
+			}
+			if basis != nil {
+				missing["basis"] = basis
+			}
+			out = append(out, missing)
+		}
+	}
+	if len(p.Nutrients) == 0 {
+		for _, n := range unknown {
+			out = append(out, copyEntry(n))
+		}
+	}
+	return out
+}
+
+func copyEntry(n map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(n))
+	for k, v := range n {
+		c[k] = v
+	}
+	return c
+}
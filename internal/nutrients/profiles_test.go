@@ -0,0 +1,111 @@
+package nutrients
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testNutrition() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"id": "protein", "name": "Protein", "amount": 5.0, "unit": "g", "basis": "100g"},
+		{"name": "Caffeine", "amount": 40.0, "unit": "mg", "basis": "100g"},
+		{"id": "energy", "name": "Energy", "amount": 120.0, "unit": "kcal", "basis": "100g"},
+		{"id": "trans-fat", "name": "Trans Fats", "amount": 0.0, "unit": "g", "basis": "100g"},
+		{"id": "energy", "name": "Energy", "amount": 500.0, "unit": "kJ", "basis": "100g"},
+	}
+}
+
+// shown renders a profile's output as id=amount pairs.
+func shown(list []map[string]interface{}) string {
+	var out []string
+	for _, n := range list {
+		key, _ := n["id"].(string)
+		if key == "" {
+			key, _ = n["name"].(string)
+		}
+		out = append(out, fmt.Sprintf("%s=%v", key, n["amount"]))
+	}
+	return fmt.Sprint(out)
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		profile, zeros, missing string
+		want                    string
+	}{
+		// registry order first, then unknown nutrients; the first energy wins
+		{"full", "", "", "[energy=120 protein=5 Caffeine=40]"},
+		{"full", ZerosKeep, "", "[energy=120 protein=5 trans-fat=0 Caffeine=40]"},
+		{"eu-label", "", "", "[energy=120 fat=<nil> saturated-fat=<nil> carbohydrates=<nil> sugars=<nil> protein=5 salt=<nil>]"},
+		{"eu-label", ZerosOmit, MissingOmit, "[energy=120 protein=5]"},
+	}
+	for _, tt := range tests {
+		p, err := SelectProfile(tt.profile, tt.zeros, tt.missing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list := testNutrition()
+		if got := shown(p.Apply(list)); got != tt.want {
+			t.Errorf("%s zeros=%q missing=%q: got %s, want %s", tt.profile, tt.zeros, tt.missing, got, tt.want)
+		}
+		if list[2]["amount"] != 120.0 || len(list) != 5 {
+			t.Errorf("%s modified its input", tt.profile)
+		}
+	}
+}
+
+func TestApplyMissingBasis(t *testing.T) {
+	p, _ := SelectProfile("eu-label", "", "")
+	out := p.Apply(testNutrition())
+	for _, n := range out {
+		if n["basis"] != "100g" {
+			t.Errorf("%v has basis %v, want the list's", n["id"], n["basis"])
+		}
+	}
+}
+
+func TestSelectProfile(t *testing.T) {
+	p, err := SelectProfile("", "", "")
+	if err != nil || p.Name != DefaultProfile {
+		t.Errorf("default profile = %q, %v", p.Name, err)
+	}
+	for _, sel := range [][3]string{{"nope", "", ""}, {"full", "drop", ""}, {"full", "", "zero"}} {
+		if _, err := SelectProfile(sel[0], sel[1], sel[2]); err == nil {
+			t.Errorf("SelectProfile%q succeeded", sel)
+		}
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "profiles.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if err := LoadProfiles(write(`{"keto": {"nutrients": ["fat", "carbohydrates", "fiber"], "missing": "null"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	p, err := SelectProfile("keto", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := shown(p.Apply(testNutrition())); got != "[fat=<nil> carbohydrates=<nil> fiber=<nil>]" {
+		t.Errorf("loaded profile shows %s", got)
+	}
+
+	for _, bad := range []string{
+		`{"x": {"nutrients": ["unobtainium"]}}`,
+		`{"x": {"zeros": "maybe"}}`,
+		`{`,
+	} {
+		if err := LoadProfiles(write(bad)); err == nil {
+			t.Errorf("loaded %s", bad)
+		}
+	}
+}
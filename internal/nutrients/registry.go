@@ -0,0 +1,172 @@
+package nutrients
+
+import (
+	"strings"
+
+	"github.com/Sush1sui/internal/units"
+)
+
+// Code is one way a provider identifies a nutrient. Nutritionix attr_ids
+// follow USDA nutrient numbers, so both live on the same code.
+type Code struct {
+	USDANumber        string
+	NutritionixAttrID int
+	// Unit is what the code's values are reported in, e.g. IU for the
+	// IU variant of vitamin D.
+	Unit string
+}
+
+// Definition is a nutrient's canonical identity across providers.
+type Definition struct {
+	ID string
+	// Names holds display names per locale; "en" is always present.
+	Names map[string]string
+	// Unit is the canonical unit responses report the nutrient in.
+	Unit string
+	// Codes are listed in order of preference.
+	Codes []Code
+	// OFFKey is the Open Food Facts nutriments key.
+	OFFKey string
+	// Aliases are lowercase provider spellings, such as USDA nutrient names.
+	Aliases []string
+}
+
+// DefaultLocale is used when a locale has no display name.
+const DefaultLocale = "en"
+
+// Name returns the display name for a locale, falling back to English.
+func (d *Definition) Name(locale string) string {
+	if name, ok := d.Names[strings.ToLower(locale)]; ok {
+		return name
+	}
+	return d.Names[DefaultLocale]
+}
+
+var (
+	byID     = map[string]*Definition{}
+	byNumber = map[string]*Definition{}
+	byAttrID = map[int]*Definition{}
+	byOFFKey = map[string]*Definition{}
+	byAlias  = map[string]*Definition{}
+)
+
+func init() {
+	for i := range definitions {
+		d := &definitions[i]
+		byID[d.ID] = d
+		byAlias[d.ID] = d
+		for _, name := range d.Names {
+			byAlias[strings.ToLower(name)] = d
+		}
+		for _, alias := range d.Aliases {
+			byAlias[alias] = d
+		}
+		for _, c := range d.Codes {
+			if c.USDANumber != "" {
+				byNumber[c.USDANumber] = d
+			}
+			if c.NutritionixAttrID != 0 {
+				byAttrID[c.NutritionixAttrID] = d
+			}
+		}
+		if d.OFFKey != "" {
+			byOFFKey[d.OFFKey] = d
+		}
+	}
+}
+
+// All returns every registered nutrient in registry order.
+func All() []*Definition {
+	all := make([]*Definition, len(definitions))
+	for i := range definitions {
+		all[i] = &definitions[i]
+	}
+	return all
+}
+
+// ByID returns the nutrient with a canonical ID.
+func ByID(id string) (*Definition, bool) {
+	d, ok := byID[id]
+	return d, ok
+}
+
+// ByUSDANumber returns the nutrient for a USDA nutrient number and the unit
+// that number is reported in.
+func ByUSDANumber(number string) (*Definition, string, bool) {
+	number = strings.TrimSpace(number)
+	d, ok := byNumber[number]
+	if !ok {
+		return nil, "", false
+	}
+	return d, d.codeUnit(func(c Code) bool { return c.USDANumber == number }), true
+}
+
+// ByNutritionixAttrID returns the nutrient for a Nutritionix attr_id and
+// the unit its values are reported in.
+func ByNutritionixAttrID(attrID int) (*Definition, string, bool) {
+	d, ok := byAttrID[attrID]
+	if !ok {
+		return nil, "", false
+	}
+	return d, d.codeUnit(func(c Code) bool { return c.NutritionixAttrID == attrID }), true
+}
+
+// ByOFFKey returns the nutrient for an Open Food Facts nutriments key.
+func ByOFFKey(key string) (*Definition, bool) {
+	d, ok := byOFFKey[key]
+	return d, ok
+}
+
+// Lookup finds a nutrient by canonical ID, any display name or provider
+// spelling, ignoring case.
+func Lookup(name string) (*Definition, bool) {
+	d, ok := byAlias[strings.ToLower(strings.TrimSpace(name))]
+	return d, ok
+}
+
+// Resolve identifies a provider nutrient by whatever it carries: canonical
+// ID, USDA nutrient number, or name.
+func Resolve(id, number, name string) (*Definition, bool) {
+	if d, ok := byID[id]; ok {
+		return d, true
+	}
+	if d, ok := byNumber[number]; ok {
+		return d, true
+	}
+	return Lookup(name)
+}
+
+func (d *Definition) codeUnit(match func(Code) bool) string {
+	for _, c := range d.Codes {
+		if match(c) && c.Unit != "" {
+			return c.Unit
+		}
+	}
+	return d.Unit
+}
+
+// Amounts reads a name/amount/unit list into registry ID → amount in the
+// nutrient's canonical unit. Nutrients outside the registry, unknown (null)
+// amounts and later duplicates are skipped.
+func Amounts(list []map[string]interface{}) map[string]float64 {
+	amounts := map[string]float64{}
+	for _, n := range list {
+		id, _ := n["id"].(string)
+		d, ok := byID[id]
+		if !ok {
+			continue
+		}
+		amount, ok := n["amount"].(float64)
+		if !ok {
+			continue
+		}
+		if _, seen := amounts[id]; seen {
+			continue
+		}
+		unit, _ := n["unit"].(string)
+		if v, err := units.ConvertNutrient(d.Name(DefaultLocale), amount, unit, d.Unit); err == nil {
+			amounts[id] = v
+		}
+	}
+	return amounts
+}
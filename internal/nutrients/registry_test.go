@@ -0,0 +1,89 @@
+package nutrients
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegistryConsistency(t *testing.T) {
+	ids := map[string]bool{}
+	for _, d := range All() {
+		if ids[d.ID] {
+			t.Errorf("duplicate ID %q", d.ID)
+		}
+		ids[d.ID] = true
+		if d.Names[DefaultLocale] == "" || d.Unit == "" {
+			t.Errorf("%s has no English name or unit", d.ID)
+		}
+		if got, ok := Lookup(d.Names[DefaultLocale]); !ok || got != d {
+			t.Errorf("Lookup of %s's name does not find it", d.ID)
+		}
+	}
+}
+
+func TestLookups(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() (*Definition, bool)
+		want string
+	}{
+		{"ID", func() (*Definition, bool) { return ByID("saturated-fat") }, "saturated-fat"},
+		{"USDA name", func() (*Definition, bool) { return Lookup("Sodium, Na") }, "sodium"},
+		{"localized name", func() (*Definition, bool) { return Lookup("protina") }, "protein"},
+		{"OFF key", func() (*Definition, bool) { return ByOFFKey("energy-kcal") }, "energy"},
+		{"resolve by number", func() (*Definition, bool) { return Resolve("", "291", "Something") }, "fiber"},
+		{"resolve by name", func() (*Definition, bool) { return Resolve("", "", "Total lipid (fat)") }, "fat"},
+	}
+	for _, tt := range tests {
+		d, ok := tt.got()
+		if !ok || d.ID != tt.want {
+			t.Errorf("%s: got %v, want %s", tt.name, d, tt.want)
+		}
+	}
+	if _, ok := Lookup("unobtainium"); ok {
+		t.Error("unknown nutrient found")
+	}
+}
+
+func TestCodeUnits(t *testing.T) {
+	if d, unit, ok := ByUSDANumber("268"); !ok || d.ID != "energy" || unit != "kJ" {
+		t.Errorf("ByUSDANumber(268) = %v, %q, %v", d, unit, ok)
+	}
+	if d, unit, ok := ByUSDANumber(" 208 "); !ok || d.ID != "energy" || unit != "kcal" {
+		t.Errorf("ByUSDANumber(208) = %v, %q, %v", d, unit, ok)
+	}
+	if d, unit, ok := ByNutritionixAttrID(307); !ok || d.ID != "sodium" || unit != "mg" {
+		t.Errorf("ByNutritionixAttrID(307) = %v, %q, %v", d, unit, ok)
+	}
+	if _, _, ok := ByNutritionixAttrID(99999); ok {
+		t.Error("unknown attr_id found")
+	}
+}
+
+func TestName(t *testing.T) {
+	d, _ := ByID("sodium")
+	for locale, want := range map[string]string{"fil": "Sodyum", "ES": "Sodio", "en": "Sodium", "de": "Sodium"} {
+		if got := d.Name(locale); got != want {
+			t.Errorf("Name(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestAmounts(t *testing.T) {
+	got := Amounts([]map[string]interface{}{
+		{"id": "sodium", "amount": 0.5, "unit": "g"},
+		{"id": "sodium", "amount": 900.0, "unit": "mg"},
+		{"id": "energy", "amount": 418.4, "unit": "kJ"},
+		{"id": "fiber", "amount": nil, "unit": "g"},
+		{"name": "Caffeine", "amount": 40.0, "unit": "mg"},
+	})
+	want := map[string]float64{"sodium": 500, "energy": 100}
+	if len(got) != len(want) {
+		t.Errorf("Amounts = %v, want %v", got, want)
+	}
+	for id, v := range want {
+		if math.Abs(got[id]-v) > 1e-9 {
+			t.Errorf("%s = %v, want %v", id, got[id], v)
+		}
+	}
+}
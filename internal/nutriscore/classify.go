@@ -0,0 +1,256 @@
+package nutriscore
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Sush1sui/internal/additives"
+	"github.com/Sush1sui/internal/ingredients"
+)
+
+// categoryTags classify a product by its Open Food Facts categories_tags.
+// A product with tags but none of these is a general food.
+var categoryTags = map[string]string{
+	"en:cheeses": Cheese,
+	"en:fats":    Fat, "en:vegetable-oils": Fat, "en:butters": Fat, "en:margarines": Fat,
+	"en:nuts": Nuts, "en:seeds": Nuts, "en:nut-butters": Nuts,
+	"en:milks": Milk, "en:dairy-drinks": Milk,
+	"en:beverages": Beverage, "en:waters": Beverage,
+}
+
+// categoryWords classify a product by the head noun of its name, so
+// "tuna in water" is not a beverage nor "tea biscuits". Names whose head
+// phrase names a dish (dishWords) are general foods whatever else they
+// mention.
+var categoryWords = map[string]string{
+	"cheese": Cheese, "cheddar": Cheese, "mozzarella": Cheese, "parmesan": Cheese,
+	"gouda": Cheese, "brie": Cheese, "camembert": Cheese, "feta": Cheese, "edam": Cheese,
+
+	"oil": Fat, "butter": Fat, "margarine": Fat, "lard": Fat, "ghee": Fat,
+	"shortening": Fat, "mayonnaise": Fat,
+
+	"nuts": Nuts, "almonds": Nuts, "walnuts": Nuts, "cashews": Nuts, "peanuts": Nuts,
+	"pistachios": Nuts, "hazelnuts": Nuts, "pecans": Nuts, "seeds": Nuts,
+
+	"milk": Milk,
+
+	"juice": Beverage, "drink": Beverage, "soda": Beverage, "cola": Beverage,
+	"tea": Beverage, "coffee": Beverage, "lemonade": Beverage, "nectar": Beverage,
+	"smoothie": Beverage, "beverage": Beverage, "water": Beverage,
+}
+
+var dishWords = map[string]bool{
+	"pizza": true, "burger": true, "cheeseburger": true, "sandwich": true, "cake": true,
+	"cheesecake": true, "pie": true, "sauce": true, "soup": true, "dip": true,
+	"crackers": true, "macaroni": true, "cookies": true, "bar": true, "bread": true,
+	"yogurt": true, "yoghurt": true, "spread": true,
+}
+
+// prepositions end the head phrase of a name: "sardines in olive oil" is
+// about sardines.
+var prepositions = map[string]bool{"in": true, "with": true, "for": true, "from": true}
+
+// packagingWords follow the head noun without being it, as in "cheddar
+// cheese slices".
+var packagingWords = map[string]bool{
+	"slice": true, "slices": true, "stick": true, "sticks": true, "block": true,
+	"pack": true, "bottle": true, "bottles": true, "can": true, "cans": true,
+	"spray": true, "shreds": true,
+}
+
+var redMeatWords = map[string]bool{
+	"beef": true, "pork": true, "lamb": true, "veal": true, "mutton": true,
+	"venison": true, "goat": true, "ham": true, "bacon": true, "steak": true,
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// headPhrase returns the words of a name up to its first comma or
+// preposition, packaging words left out. USDA names lead with the food
+// ("Milk, whole"), others qualify it after a preposition ("Tuna in water").
+func headPhrase(name string) []string {
+	first, _, _ := strings.Cut(name, ",")
+	var phrase []string
+	for _, w := range words(first) {
+		if prepositions[w] && len(phrase) > 0 {
+			break
+		}
+		phrase = append(phrase, w)
+	}
+	for len(phrase) > 1 && packagingWords[phrase[len(phrase)-1]] {
+		phrase = phrase[:len(phrase)-1]
+	}
+	return phrase
+}
+
+// headNoun is the last word of the head phrase, "" for a name without
+// words.
+func headNoun(name string) string {
+	phrase := headPhrase(name)
+	if len(phrase) == 0 {
+		return ""
+	}
+	return phrase[len(phrase)-1]
+}
+
+// Classify guesses a product's category, and whether it is red meat. Open
+// Food Facts categories decide when there are any, the most specific
+// first; otherwise the head noun of the name does. liquid reports a
+// serving measured in milliliters, which makes an otherwise unclassified
+// product a beverage.
+func Classify(name string, categories []string, liquid bool) (category string, redMeat bool) {
+	for _, w := range words(name) {
+		if redMeatWords[w] {
+			redMeat = true
+		}
+	}
+	if len(categories) > 0 {
+		for i := len(categories) - 1; i >= 0; i-- {
+			if c, ok := categoryTags[strings.ToLower(categories[i])]; ok {
+				return c, redMeat
+			}
+		}
+		return Food, redMeat
+	}
+
+	for _, w := range headPhrase(name) {
+		if dishWords[w] {
+			return Food, redMeat
+		}
+	}
+	head := headNoun(name)
+	if c, ok := categoryWords[head]; ok {
+		return c, redMeat
+	}
+	if c, ok := categoryWords[strings.TrimSuffix(head, "s")]; ok {
+		return c, redMeat
+	}
+	if liquid {
+		return Beverage, redMeat
+	}
+	return Food, redMeat
+}
+
+// fruitVegLegumeWords are ingredients counted towards the fruit, vegetable
+// and legume share, matched as whole words in singular or plural. Potatoes,
+// cereals, nuts and spices do not count.
+var fruitVegLegumeWords = []string{
+	"apple", "apricot", "avocado", "banana", "berry", "cherry", "cranberry", "grape",
+	"guava", "kiwi", "lemon", "lime", "mango", "melon", "orange", "papaya", "peach",
+	"pear", "pineapple", "plum", "pomegranate", "raisin", "strawberry", "blueberry",
+	"raspberry", "calamansi",
+	"tomato", "carrot", "onion", "spinach", "lettuce", "cabbage", "broccoli",
+	"cauliflower", "pumpkin", "squash", "zucchini", "cucumber", "eggplant", "celery",
+	"kale", "beet", "leek", "asparagus", "mushroom", "okra", "radish",
+	"bell pepper", "sweet pepper", "sweet corn",
+	"pea", "bean", "lentil", "chickpea", "soybean",
+}
+
+// processedWords mark ingredients that are derived from a fruit or
+// vegetable but do not count as one.
+var processedWords = []string{"flavor", "flavour", "extract", "powder", "oil", "starch", "fiber", "fibre", "pectin", "cocoa", "coffee", "vanilla", "jelly bean"}
+
+// EstimateFruitVegLegumes estimates the percentage of fruit, vegetables
+// and legumes in an ingredient statement. Stated percentages are used as
+// given; the rest of the recipe is shared among unlabelled ingredients in
+// decreasing proportion to their position, since labels list ingredients
+// by weight, capped by any "2% or less" boundary.
+func EstimateFruitVegLegumes(statement string) float64 {
+	list := ingredients.Parse(statement).Ingredients
+	if len(list) == 0 {
+		return 0
+	}
+
+	shares := make([]float64, len(list))
+	stated := 0.0
+	var unlabelled []int
+	for i, ing := range list {
+		if ing.Percent != nil {
+			shares[i] = *ing.Percent
+			stated += *ing.Percent
+			continue
+		}
+		unlabelled = append(unlabelled, i)
+	}
+	if remaining := 100 - stated; remaining > 0 && len(unlabelled) > 0 {
+		weights := 0.0
+		for rank := range unlabelled {
+			weights += float64(len(unlabelled) - rank)
+		}
+		for rank, i := range unlabelled {
+			shares[i] = remaining * float64(len(unlabelled)-rank) / weights
+			if limit := list[i].AtMost; limit != nil {
+				shares[i] = min(shares[i], *limit)
+			}
+		}
+	}
+
+	total := 0.0
+	for i, ing := range list {
+		if isFruitVegLegume(ing.Text) {
+			total += shares[i]
+		}
+	}
+	return round(min(total, 100), 1)
+}
+
+func isFruitVegLegume(ingredient string) bool {
+	name := strings.ToLower(ingredient)
+	for _, w := range processedWords {
+		if strings.Contains(name, w) {
+			return false
+		}
+	}
+	text := " " + strings.Join(words(name), " ") + " "
+	for _, w := range fruitVegLegumeWords {
+		forms := []string{w, w + "s", w + "es"}
+		if strings.HasSuffix(w, "y") {
+			forms = append(forms, strings.TrimSuffix(w, "y")+"ies")
+		}
+		for _, f := range forms {
+			if strings.Contains(text, " "+f+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasSweeteners reports whether an ingredient statement lists an additive
+// of the sweetener class.
+func HasSweeteners(statement string) bool {
+	for _, d := range additives.Detect(statement) {
+		if d.Class == additives.Sweetener {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe fills in the category, red meat, sweetener and fruit, vegetable
+// and legume fields of an input from a product's name, Open Food Facts
+// categories and ingredients. Unsweetened, energy-free beverages that are
+// waters by name or category are plain water.
+func (in *Input) Describe(name, ingredients string, categories []string, liquid bool) {
+	in.Category, in.RedMeat = Classify(name, categories, liquid)
+	in.FruitVegLegumes = EstimateFruitVegLegumes(ingredients)
+	in.Sweeteners = HasSweeteners(ingredients)
+	if in.Category == Beverage && in.EnergyKJ == 0 && in.Sugars == 0 && !in.Sweeteners {
+		if headNoun(name) == "water" || containsTag(categories, "en:waters") {
+			in.Category = Water
+		}
+	}
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package nutriscore
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		liquid   bool
+		category string
+		redMeat  bool
+	}{
+		{"Aged Cheddar", false, Cheese, false},
+		{"Extra Virgin Olive Oil", false, Fat, false},
+		{"Roasted Almonds", false, Nuts, false},
+		{"Whole Milk", true, Milk, false},
+		{"Orange Juice", true, Beverage, false},
+		{"Sparkling water", true, Beverage, false},
+		{"Kombucha", true, Beverage, false},
+		{"Kombucha", false, Food, false},
+		// dishes are general foods whatever they contain
+		{"Cheese pizza", false, Food, false},
+		{"Peanut butter cookies", false, Food, false},
+		{"Beef steak", false, Food, true},
+		{"Bacon cheeseburger", false, Food, true},
+		{"Macaroni and cheese", false, Food, false},
+		// the head noun decides, not any word of the name
+		{"Tuna in water", false, Food, false},
+		{"Water chestnuts", false, Food, false},
+		{"Sardines in olive oil", false, Food, false},
+		{"Tea biscuits", false, Food, false},
+		{"Chocolate milk", true, Milk, false},
+		{"Iced tea", true, Beverage, false},
+		{"Ice tea", false, Beverage, false},
+		{"Ice cream", false, Food, false},
+		{"Cream cheese", false, Cheese, false},
+		{"Cheddar cheese slices", false, Cheese, false},
+		{"Milk chocolate", false, Food, false},
+		// USDA names lead with the food
+		{"Milk, whole, 3.25% milkfat", false, Milk, false},
+		{"Beverages, tea, black, brewed", false, Beverage, false},
+		{"Fish, tuna, light, canned in water", false, Food, false},
+		{"", false, Food, false},
+	}
+	for _, tt := range tests {
+		category, redMeat := Classify(tt.name, nil, tt.liquid)
+		if category != tt.category || redMeat != tt.redMeat {
+			t.Errorf("Classify(%q, %v) = %s, %v; want %s, %v", tt.name, tt.liquid, category, redMeat, tt.category, tt.redMeat)
+		}
+	}
+}
+
+func TestClassifyCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []string
+		category   string
+	}{
+		{"Tuna in water", []string{"en:seafood", "en:fishes", "en:canned-fishes"}, Food},
+		{"Nonna's dressing", []string{"en:fats", "en:vegetable-fats", "en:vegetable-oils", "en:olive-oils"}, Fat},
+		{"Choco", []string{"en:beverages", "en:dairies", "en:milks", "en:flavoured-milks"}, Milk},
+		{"Cheese crackers", []string{"EN:CHEESES"}, Cheese},
+		{"Sparkling", []string{"en:beverages", "en:waters"}, Beverage},
+		// tags without a special category make a general food, even if
+		// the name says otherwise
+		{"Orange juice", []string{"en:plant-based-foods-and-beverages"}, Food},
+	}
+	for _, tt := range tests {
+		if category, _ := Classify(tt.name, tt.categories, true); category != tt.category {
+			t.Errorf("Classify(%q, %v) = %s, want %s", tt.name, tt.categories, category, tt.category)
+		}
+	}
+}
+
+func TestEstimateFruitVegLegumes(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      float64
+	}{
+		{"", 0},
+		{"Apples", 100},
+		{"Tomatoes (60%), water, onions, salt", 73.3},
+		{"Sugar, water, apple flavor, citric acid", 0},
+		{"Water, sugar, contains 2% or less of: carrot, salt", 2},
+		{"Chickpeas, tahini, lemon juice 5%, garlic", 52.5},
+	}
+	for _, tt := range tests {
+		if got := EstimateFruitVegLegumes(tt.statement); got != tt.want {
+			t.Errorf("EstimateFruitVegLegumes(%q) = %v, want %v", tt.statement, got, tt.want)
+		}
+	}
+}
+
+func TestHasSweeteners(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      bool
+	}{
+		{"Carbonated water, caramel color, aspartame, acesulfame K", true},
+		{"Water, sweetener (E955)", true},
+		{"Water, stevia leaf extract", true},
+		{"Sugar, water, E330", false},
+		// E-numbers are whole codes, not prefixes of other numbers
+		{"Milk, E9500", false},
+		{"Sugar, salt", false},
+	}
+	for _, tt := range tests {
+		if got := HasSweeteners(tt.statement); got != tt.want {
+			t.Errorf("HasSweeteners(%q) = %v, want %v", tt.statement, got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name, ingredients string
+		in                Input
+		category          string
+		sweeteners        bool
+	}{
+		{"Spring water", "Water", Input{}, Water, false},
+		{"Flavored water", "Water, sucralose", Input{}, Beverage, true},
+		{"Vitamin water", "Water, sugar", Input{EnergyKJ: 80, Sugars: 4.5}, Beverage, false},
+		{"Tomato soup", "Tomatoes, water, salt", Input{EnergyKJ: 150}, Food, false},
+	}
+	for _, tt := range tests {
+		in := tt.in
+		in.Describe(tt.name, tt.ingredients, nil, true)
+		if in.Category != tt.category || in.Sweeteners != tt.sweeteners {
+			t.Errorf("Describe(%q) = %s sweeteners %v, want %s sweeteners %v", tt.name, in.Category, in.Sweeteners, tt.category, tt.sweeteners)
+		}
+	}
+
+	var in Input
+	in.Describe("Montagne", "", []string{"en:beverages", "en:waters", "en:spring-waters"}, true)
+	if in.Category != Water {
+		t.Errorf("water by category = %s, want %s", in.Category, Water)
+	}
+}
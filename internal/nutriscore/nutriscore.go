@@ -0,0 +1,337 @@
+package nutriscore
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Sush1sui/internal/nutrients"
+	"github.com/Sush1sui/internal/units"
+)
+
+// Algorithm versions: the original 2017 computation and the 2023 revision
+// (2024 for beverages), which reworked sugars, salt, protein and the
+// fats, oils, nuts and seeds category.
+const (
+	Version2017 = "2017"
+	Version2023 = "2023"
+)
+
+// DefaultVersion is used when a request names none.
+const DefaultVersion = Version2023
+
+// Categories decide which point tables and grade thresholds apply. Nuts and
+// milk drinks are general foods under the 2017 rules; 2023 moved them to
+// the fats and beverages categories.
+const (
+	Food     = "food"
+	Beverage = "beverage"
+	Water    = "water"
+	Cheese   = "cheese"
+	Fat      = "fat"
+	Nuts     = "nuts"
+	Milk     = "milk"
+)
+
+// Input is what a score is computed from, every amount per 100 g (or
+// 100 ml for beverages).
+type Input struct {
+	EnergyKJ     float64
+	Sugars       float64
+	SaturatedFat float64
+	Fat          float64
+	Salt         float64
+	Fiber        float64
+	Protein      float64
+	// FruitVegLegumes is the percentage of fruit, vegetables and legumes.
+	FruitVegLegumes float64
+	Category        string
+	RedMeat         bool
+	// Sweeteners reports non-nutritive sweeteners, penalized in 2023
+	// beverages.
+	Sweeteners bool
+}
+
+// Component is one line of the point breakdown.
+type Component struct {
+	Name      string  `json:"name"`
+	Value     float64 `json:"value"`
+	Unit      string  `json:"unit"`
+	Points    int     `json:"points"`
+	MaxPoints int     `json:"maxPoints"`
+}
+
+// Result is a computed Nutri-Score with its point breakdown.
+type Result struct {
+	Version        string      `json:"version"`
+	Category       string      `json:"category"`
+	Score          int         `json:"score"`
+	Grade          string      `json:"grade"`
+	NegativePoints int         `json:"negativePoints"`
+	PositivePoints int         `json:"positivePoints"`
+	ProteinCounted bool        `json:"proteinCounted"`
+	Negative       []Component `json:"negative"`
+	Positive       []Component `json:"positive"`
+}
+
+// points counts the thresholds a value exceeds.
+func points(value float64, thresholds []float64) int {
+	p := 0
+	for _, t := range thresholds {
+		if value > t {
+			p++
+		}
+	}
+	return p
+}
+
+func component(name string, value float64, unit string, thresholds []float64) Component {
+	return Component{name, round(value, 2), unit, points(value, thresholds), len(thresholds)}
+}
+
+func steps(step float64, n int) []float64 {
+	t := make([]float64, n)
+	for i := range t {
+		t[i] = step * float64(i+1)
+	}
+	return t
+}
+
+// saturatedRatio is saturated fat as a percentage of total fat.
+func saturatedRatio(in Input) float64 {
+	if in.Fat <= 0 {
+		return 0
+	}
+	return in.SaturatedFat / in.Fat * 100
+}
+
+var ratioThresholds = []float64{9.99, 15.99, 21.99, 27.99, 33.99, 39.99, 45.99, 51.99, 57.99, 63.99}
+
+// CheckVersion reports an error for unknown algorithm versions; "" is the
+// default version.
+func CheckVersion(version string) error {
+	switch version {
+	case "", Version2017, Version2023:
+		return nil
+	}
+	return fmt.Errorf("unknown Nutri-Score version %q (available: %s, %s)", version, Version2017, Version2023)
+}
+
+// Compute scores a product with the given algorithm version.
+func Compute(in Input, version string) (Result, error) {
+	if err := CheckVersion(version); err != nil {
+		return Result{}, err
+	}
+	if version == Version2017 {
+		return compute2017(in), nil
+	}
+	return compute2023(in), nil
+}
+
+func compute2017(in Input) Result {
+	r := Result{Version: Version2017, Category: in.Category}
+	switch in.Category {
+	case Nuts, Milk:
+		r.Category = Food
+	case Water:
+		r.Grade = "A"
+		return r
+	}
+
+	sodium := units.SaltToSodium(in.Salt) * 1000
+	if r.Category == Beverage {
+		// beverages score a point for any energy or sugar at all
+		r.Negative = []Component{
+			component("energy", in.EnergyKJ, units.Kilojoule, append([]float64{0}, steps(30, 9)...)),
+			component("sugars", in.Sugars, units.Gram, append([]float64{0}, steps(1.5, 9)...)),
+		}
+	} else {
+		r.Negative = []Component{
+			component("energy", in.EnergyKJ, units.Kilojoule, steps(335, 10)),
+			component("sugars", in.Sugars, units.Gram, steps(4.5, 10)),
+		}
+	}
+	if r.Category == Fat {
+		r.Negative = append(r.Negative, component("saturatedFatRatio", round(saturatedRatio(in), 1), "%", ratioThresholds))
+	} else {
+		r.Negative = append(r.Negative, component("saturatedFat", in.SaturatedFat, units.Gram, steps(1, 10)))
+	}
+	r.Negative = append(r.Negative, component("sodium", round(sodium, 1), units.Milligram, steps(90, 10)))
+
+	fvl := component("fruitsVegetablesLegumes", in.FruitVegLegumes, "%", []float64{40, 60, 80})
+	fvl.Points = []int{0, 1, 2, 5}[fvl.Points]
+	fvl.MaxPoints = 5
+	if r.Category == Beverage {
+		fvl.Points = []int{0, 2, 4, 10}[points(in.FruitVegLegumes, []float64{40, 60, 80})]
+		fvl.MaxPoints = 10
+	}
+	r.Positive = []Component{
+		fvl,
+		component("fiber", in.Fiber, units.Gram, []float64{0.9, 1.9, 2.8, 3.7, 4.7}),
+		component("protein", in.Protein, units.Gram, []float64{1.6, 3.2, 4.8, 6.4, 8.0}),
+	}
+
+	r.NegativePoints = sum(r.Negative)
+	r.ProteinCounted = r.NegativePoints < 11 || fvl.Points >= 5 || r.Category == Cheese
+	r.total()
+	if r.Category == Beverage {
+		r.Grade = grade(r.Score, 1, 5, 9)
+	} else {
+		r.Grade = grade(r.Score, -1, 2, 10, 18)
+	}
+	return r
+}
+
+func compute2023(in Input) Result {
+	r := Result{Version: Version2023, Category: in.Category}
+	switch in.Category {
+	case Nuts:
+		r.Category = Fat
+	case Milk:
+		r.Category = Beverage
+	case Water:
+		r.Grade = "A"
+		return r
+	}
+
+	switch r.Category {
+	case Beverage:
+		r.Negative = []Component{
+			component("energy", in.EnergyKJ, units.Kilojoule, []float64{30, 90, 150, 210, 240, 270, 300, 330, 360, 390}),
+			component("sugars", in.Sugars, units.Gram, []float64{0.5, 2, 3.5, 5, 6, 7, 8, 9, 10, 11}),
+			component("saturatedFat", in.SaturatedFat, units.Gram, steps(1, 10)),
+		}
+		sweeteners := Component{Name: "sweeteners", MaxPoints: 4}
+		if in.Sweeteners {
+			sweeteners.Value, sweeteners.Points = 1, 4
+		}
+		r.Negative = append(r.Negative, sweeteners)
+	case Fat:
+		r.Negative = []Component{
+			component("energyFromSaturates", round(in.SaturatedFat*37, 1), units.Kilojoule, steps(120, 10)),
+			component("sugars", in.Sugars, units.Gram, sugars2023),
+			component("saturatedFatRatio", round(saturatedRatio(in), 1), "%", ratioThresholds),
+		}
+	default:
+		r.Negative = []Component{
+			component("energy", in.EnergyKJ, units.Kilojoule, steps(335, 10)),
+			component("sugars", in.Sugars, units.Gram, sugars2023),
+			component("saturatedFat", in.SaturatedFat, units.Gram, steps(1, 10)),
+		}
+	}
+	r.Negative = append(r.Negative, component("salt", in.Salt, units.Gram, steps(0.2, 20)))
+
+	fvl := component("fruitsVegetablesLegumes", in.FruitVegLegumes, "%", []float64{40, 60, 80})
+	protein := component("protein", in.Protein, units.Gram, []float64{2.4, 4.8, 7.2, 9.6, 12, 14, 17})
+	if r.Category == Beverage {
+		fvl.Points = []int{0, 2, 4, 6}[fvl.Points]
+		fvl.MaxPoints = 6
+		protein = component("protein", in.Protein, units.Gram, []float64{1.2, 1.5, 1.8, 2.1, 2.4, 2.7, 3.0})
+	} else {
+		fvl.Points = []int{0, 1, 2, 5}[fvl.Points]
+		fvl.MaxPoints = 5
+	}
+	if in.RedMeat && protein.Points > 2 {
+		protein.Points = 2
+	}
+	r.Positive = []Component{
+		fvl,
+		component("fiber", in.Fiber, units.Gram, []float64{3.0, 4.1, 5.2, 6.3, 7.4}),
+		protein,
+	}
+
+	r.NegativePoints = sum(r.Negative)
+	switch r.Category {
+	case Beverage, Cheese:
+		r.ProteinCounted = true
+	case Fat:
+		r.ProteinCounted = r.NegativePoints < 7
+	default:
+		r.ProteinCounted = r.NegativePoints < 11
+	}
+	r.total()
+	switch r.Category {
+	case Beverage:
+		r.Grade = grade(r.Score, 2, 6, 9)
+	case Fat:
+		r.Grade = grade(r.Score, -6, 2, 10, 18)
+	default:
+		r.Grade = grade(r.Score, 0, 2, 10, 18)
+	}
+	return r
+}
+
+var sugars2023 = []float64{3.4, 6.8, 10, 14, 17, 20, 24, 27, 31, 34, 37, 41, 44, 48, 51}
+
+func (r *Result) total() {
+	r.PositivePoints = 0
+	for _, c := range r.Positive {
+		if c.Name == "protein" && !r.ProteinCounted {
+			continue
+		}
+		r.PositivePoints += c.Points
+	}
+	r.Score = r.NegativePoints - r.PositivePoints
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+func sum(components []Component) int {
+	total := 0
+	for _, c := range components {
+		total += c.Points
+	}
+	return total
+}
+
+// grade maps a score to A-E given the highest score of each grade. With
+// four bounds the first is A's; with three, A is reserved for water.
+func grade(score int, bounds ...int) string {
+	grades := "ABCDE"
+	if len(bounds) == 3 {
+		grades = "BCDE"
+	}
+	for i, b := range bounds {
+		if score <= b {
+			return string(grades[i])
+		}
+	}
+	return string(grades[len(grades)-1])
+}
+
+// InputFrom reads the nutrients of a per-100 g name/amount/unit list.
+// Energy, sugars, saturated fat and salt (or sodium) are required; fiber,
+// protein and fat count as 0 when absent.
+func InputFrom(per100g []map[string]interface{}) (Input, error) {
+	amounts := nutrients.Amounts(per100g)
+	if kcal, ok := amounts["energy"]; ok {
+		amounts["energy"], _ = units.Convert(kcal, units.Kcal, units.Kilojoule)
+	}
+	if _, ok := amounts["salt"]; !ok {
+		if sodium, ok := amounts["sodium"]; ok {
+			amounts["salt"] = units.SodiumToSalt(sodium) / 1000
+		}
+	}
+
+	var missing []string
+	for _, id := range []string{"energy", "sugars", "saturated-fat", "salt"} {
+		if _, ok := amounts[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return Input{}, fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	return Input{
+		EnergyKJ:     round(amounts["energy"], 1),
+		Sugars:       amounts["sugars"],
+		SaturatedFat: amounts["saturated-fat"],
+		Fat:          amounts["fat"],
+		Salt:         round(amounts["salt"], 3),
+		Fiber:        amounts["fiber"],
+		Protein:      amounts["protein"],
+	}, nil
+}
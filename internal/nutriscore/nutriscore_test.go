@@ -0,0 +1,112 @@
+package nutriscore
+
+import "testing"
+
+func TestCompute(t *testing.T) {
+	biscuit := Input{EnergyKJ: 1000, Sugars: 10, SaturatedFat: 3, Fat: 8, Salt: 1, Fiber: 2, Protein: 5, Category: Food}
+	tests := []struct {
+		name     string
+		in       Input
+		version  string
+		negative int
+		positive int
+		score    int
+		grade    string
+	}{
+		{"food 2017", biscuit, Version2017, 10, 5, 5, "C"},
+		{"food 2023", biscuit, Version2023, 10, 2, 8, "C"},
+		{"cola 2023", Input{EnergyKJ: 180, Sugars: 10.6, Salt: 0.01, Category: Beverage}, Version2023, 12, 0, 12, "E"},
+		{"diet cola 2023", Input{EnergyKJ: 1, Category: Beverage, Sweeteners: true}, Version2023, 4, 0, 4, "C"},
+		{"diet cola 2017", Input{EnergyKJ: 1, Category: Beverage, Sweeteners: true}, Version2017, 1, 0, 1, "B"},
+		{"water", Input{Category: Water}, Version2023, 0, 0, 0, "A"},
+		{"olive oil 2023", Input{EnergyKJ: 3700, SaturatedFat: 14, Fat: 100, Category: Fat}, Version2023, 5, 0, 5, "C"},
+		// 2023 counts red meat protein for at most 2 points
+		{"steak 2023", Input{EnergyKJ: 600, SaturatedFat: 2.5, Fat: 6, Salt: 0.1, Protein: 25, Category: Food, RedMeat: true}, Version2023, 3, 2, 1, "B"},
+		{"chicken 2023", Input{EnergyKJ: 600, SaturatedFat: 2.5, Fat: 6, Salt: 0.1, Protein: 25, Category: Food}, Version2023, 3, 7, -4, "A"},
+		// protein stops counting at 11 negative points unless fruit and
+		// vegetables score 5
+		{"salty snack 2017", Input{EnergyKJ: 2000, Sugars: 5, SaturatedFat: 5, Salt: 2, Protein: 8, Category: Food}, Version2017, 18, 0, 18, "D"},
+		{"cheese 2017", Input{EnergyKJ: 1700, SaturatedFat: 20, Fat: 33, Salt: 1.8, Protein: 25, Category: Cheese}, Version2017, 22, 5, 17, "D"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Compute(tt.in, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.NegativePoints != tt.negative || r.PositivePoints != tt.positive || r.Score != tt.score || r.Grade != tt.grade {
+				t.Errorf("got N=%d P=%d score %d grade %s, want N=%d P=%d score %d grade %s\n%+v",
+					r.NegativePoints, r.PositivePoints, r.Score, r.Grade, tt.negative, tt.positive, tt.score, tt.grade, r)
+			}
+		})
+	}
+}
+
+func TestCategoryByVersion(t *testing.T) {
+	tests := []struct {
+		category, version, want string
+	}{
+		{Nuts, Version2017, Food},
+		{Nuts, Version2023, Fat},
+		{Milk, Version2017, Food},
+		{Milk, Version2023, Beverage},
+	}
+	for _, tt := range tests {
+		r, _ := Compute(Input{Category: tt.category}, tt.version)
+		if r.Category != tt.want {
+			t.Errorf("%s in %s scored as %s, want %s", tt.category, tt.version, r.Category, tt.want)
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	for _, v := range []string{"", Version2017, Version2023} {
+		if err := CheckVersion(v); err != nil {
+			t.Errorf("CheckVersion(%q): %v", v, err)
+		}
+	}
+	if _, err := Compute(Input{}, "2020"); err == nil {
+		t.Error("unknown version computed")
+	}
+}
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		score  int
+		bounds []int
+		want   string
+	}{
+		{-1, []int{-1, 2, 10, 18}, "A"},
+		{0, []int{-1, 2, 10, 18}, "B"},
+		{18, []int{-1, 2, 10, 18}, "D"},
+		{19, []int{-1, 2, 10, 18}, "E"},
+		{-5, []int{2, 6, 9}, "B"},
+		{10, []int{2, 6, 9}, "E"},
+	}
+	for _, tt := range tests {
+		if got := grade(tt.score, tt.bounds...); got != tt.want {
+			t.Errorf("grade(%d, %v) = %s, want %s", tt.score, tt.bounds, got, tt.want)
+		}
+	}
+}
+
+func TestInputFrom(t *testing.T) {
+	in, err := InputFrom([]map[string]interface{}{
+		{"id": "energy", "amount": 100.0, "unit": "kcal"},
+		{"id": "sugars", "amount": 5.0, "unit": "g"},
+		{"id": "saturated-fat", "amount": 1.5, "unit": "g"},
+		{"id": "sodium", "amount": 400.0, "unit": "mg"},
+		{"id": "protein", "amount": 3.0, "unit": "g"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Input{EnergyKJ: 418.4, Sugars: 5, SaturatedFat: 1.5, Salt: 1, Protein: 3}
+	if in != want {
+		t.Errorf("InputFrom = %+v, want %+v", in, want)
+	}
+
+	if _, err := InputFrom([]map[string]interface{}{{"id": "energy", "amount": 100.0, "unit": "kcal"}}); err == nil {
+		t.Error("input without sugars, saturated fat and salt accepted")
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/Sush1sui/internal/alternatives"
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/nutrients"
+)
+
+const (
+	maxAlternatives = 10
+	// alternativePoolSize is how many search results each provider adds to
+	// the candidates.
+	alternativePoolSize = 25
+)
+
+// parseAlternatives reads the "alternatives" query parameter: how many
+// healthier products to suggest, 0 when absent.
+func parseAlternatives(param string) (int, error) {
+	if param == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(param)
+	if err != nil || n < 0 || n > maxAlternatives {
+		return 0, fmt.Errorf("Invalid alternatives %q (0-%d)", param, maxAlternatives)
+	}
+	return n, nil
+}
+
+// alternativesFor suggests up to limit healthier products like p. The
+// candidates are the cached products plus a USDA Branded search for its
+// name and an Open Food Facts listing of its category, or a search for its
+// name when it has no Open Food Facts category.
+func alternativesFor(ctx context.Context, p *common.Product, limit int) []alternatives.Suggestion {
+	var (
+		mu     sync.Mutex
+		found  []*common.Product
+		wg     sync.WaitGroup
+		search = func(name string, fetch func() ([]*common.Product, error)) {
+			defer wg.Done()
+			products, err := fetch()
+			if err != nil {
+				fmt.Printf("alternativesFor: %s error: %v\n", name, err)
+				return
+			}
+			mu.Lock()
+			found = append(found, products...)
+			mu.Unlock()
+		}
+	)
+	wg.Add(2)
+	go search("USDA", func() ([]*common.Product, error) {
+		return searchSource(ctx, "usda", p.Name, []string{"Branded"}, 1, alternativePoolSize)
+	})
+	go search("Open Food Facts", func() ([]*common.Product, error) {
+		if p.Source != "openfoodfacts" || p.Category == "" {
+			return searchSource(ctx, "openfoodfacts", p.Name, nil, 1, alternativePoolSize)
+		}
+		items, err := searchOFFCategory(ctx, p.Category, alternativePoolSize)
+		var products []*common.Product
+		for _, item := range items {
+			products = append(products, item.product())
+		}
+		return products, err
+	})
+	wg.Wait()
+
+	// the pool is cached for later scans but not offered by autocomplete
+	for _, c := range found {
+		cacheProduct(c)
+	}
+
+	seen := map[string]bool{p.DedupKey(): true}
+	var candidates []alternatives.Candidate
+	for _, c := range append(cachedProducts(), found...) {
+		if c.Name == "" || seen[c.DedupKey()] {
+			continue
+		}
+		seen[c.DedupKey()] = true
+		candidates = append(candidates, candidate(c))
+	}
+	return alternatives.Suggest(candidate(p), candidates, limit)
+}
+
+func candidate(p *common.Product) alternatives.Candidate {
+	per100g, _ := p.DualNutrition()
+	return alternatives.Candidate{
+		ID:       p.ID,
+		Name:     p.Name,
+		Brand:    p.Brand,
+		Source:   p.Source,
+		Category: p.Category,
+		Amounts:  nutrients.Amounts(per100g),
+	}
+}
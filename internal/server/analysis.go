@@ -0,0 +1,186 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Sush1sui/internal/additives"
+	"github.com/Sush1sui/internal/allergens"
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/diet"
+	"github.com/Sush1sui/internal/frontofpack"
+	"github.com/Sush1sui/internal/glycemic"
+	"github.com/Sush1sui/internal/health"
+	"github.com/Sush1sui/internal/ingredients"
+	"github.com/Sush1sui/internal/nova"
+	"github.com/Sush1sui/internal/nutrients"
+	"github.com/Sush1sui/internal/nutriscore"
+)
+
+// foodFacts is what the per-product analyses work from.
+type foodFacts struct {
+	Name        string
+	Ingredients string
+	Per100g     []map[string]interface{}
+	// Liquid reports a serving measured in milliliters.
+	Liquid bool
+	// LabelTags, AnalysisTags and Categories are provider tags; see
+	// common.Product.
+	LabelTags    []string
+	AnalysisTags []string
+	Categories   []string
+	// NovaGroup is the provider's NOVA group, 0 when unknown.
+	NovaGroup int
+	// FdcID is the USDA food the facts come from, 0 for other providers.
+	FdcID int
+	// ServingGrams is the serving weight, 0 when unknown.
+	ServingGrams float64
+}
+
+// productFacts describes a looked-up product.
+func productFacts(p *common.Product) foodFacts {
+	per100g, _ := p.DualNutrition()
+	serving, _ := common.ParseServingSize(p.ServingSize)
+	f := foodFacts{
+		Name:         p.Name,
+		Ingredients:  p.Ingredients,
+		Per100g:      per100g,
+		Liquid:       serving.Milliliters > 0 && serving.Grams == 0,
+		LabelTags:    p.LabelTags,
+		AnalysisTags: p.AnalysisTags,
+		Categories:   p.Categories,
+		NovaGroup:    p.NovaGroup,
+		ServingGrams: p.ServingWeight(),
+	}
+	if id, ok := strings.CutPrefix(p.ID, "usda:"); ok {
+		f.FdcID, _ = strconv.Atoi(id)
+	}
+	return f
+}
+
+// scannedFacts describes a lookupScannedFood result from its "foodName",
+// "ingredients", "per100g", "fdcId" and "servingWeight".
+func scannedFacts(results map[string]interface{}) foodFacts {
+	chunks, _ := results["per100g"].([][]map[string]interface{})
+	var per100g []map[string]interface{}
+	for _, chunk := range chunks {
+		per100g = append(per100g, chunk...)
+	}
+	name, _ := results["foodName"].(string)
+	ingredients, _ := results["ingredients"].(string)
+	fdcID, _ := results["fdcId"].(int)
+	grams, _ := results["servingWeight"].(float64)
+	return foodFacts{Name: name, Ingredients: ingredients, Per100g: per100g, FdcID: fdcID, ServingGrams: grams}
+}
+
+// analyze adds the parsed ingredient tree, Nutri-Score, front-of-pack
+// labels, allergens, additives, diet verdicts, NOVA group and carbohydrate
+// counts to a product's response data, and personalized "alerts" when the
+// request has a health profile.
+func analyze(data map[string]interface{}, f foodFacts, opts nutritionOptions) {
+	data["ingredientTree"] = ingredients.Parse(f.Ingredients)
+	data["nutriScore"] = nutriScoreFor(f, opts.NutriScore)
+	data["labels"] = frontOfPackLabels(f, opts.Labels)
+	data["allergens"] = allergens.Detect(f.Ingredients, opts.Allergens...)
+	data["additives"] = additives.Detect(f.Ingredients)
+	data["diets"] = diet.Check(diet.Input{
+		Ingredients:  f.Ingredients,
+		LabelTags:    f.LabelTags,
+		AnalysisTags: f.AnalysisTags,
+		Amounts:      nutrients.Amounts(f.Per100g),
+	}, opts.Diets)
+	data["nova"] = novaFor(f)
+	data["carbs"] = carbsFor(f)
+	if opts.Health != nil {
+		data["alerts"] = health.Alerts(*opts.Health, health.Input{
+			Name:         f.Name,
+			Ingredients:  f.Ingredients,
+			Amounts:      nutrients.Amounts(f.Per100g),
+			ServingGrams: f.ServingGrams,
+			FdcID:        f.FdcID,
+		})
+	}
+}
+
+// nutriScoreFor computes the Nutri-Score of a food from its per-100 g
+// nutrients, or returns nil when they lack energy, sugars, saturated fat or
+// salt. The category and fruit, vegetable and legume share are estimated
+// from the name, categories and ingredients.
+func nutriScoreFor(f foodFacts, version string) interface{} {
+	in, err := nutriscore.InputFrom(f.Per100g)
+	if err != nil {
+		return nil
+	}
+	in.Describe(f.Name, f.Ingredients, f.Categories, f.Liquid)
+	result, err := nutriscore.Compute(in, version)
+	if err != nil {
+		return nil
+	}
+	return result
+}
+
+// novaFor passes through the provider's NOVA group, estimating one from
+// the ingredients when there is none. It returns nil when neither is
+// available.
+func novaFor(f foodFacts) interface{} {
+	if result, ok := nova.FromGroup(f.NovaGroup); ok {
+		return result
+	}
+	if result, ok := nova.Estimate(f.Ingredients); ok {
+		return result
+	}
+	return nil
+}
+
+// carbsFor counts the carbohydrates of a food per 100 g and per serving
+// with their exchanges, and estimates the glycemic load from the GI of the
+// reference food matching its FDC ID or name. It returns nil when the food
+// reports no carbohydrates; "glycemic" is nil when no reference matches.
+func carbsFor(f foodFacts) interface{} {
+	per100g, ok := glycemic.Count(nutrients.Amounts(f.Per100g))
+	if !ok {
+		return nil
+	}
+	carbs := map[string]interface{}{
+		"per100g":       per100g,
+		"perServing":    nil,
+		"exchangeGrams": glycemic.ExchangeGrams,
+		"glycemic":      nil,
+	}
+	var perServing glycemic.Carbs
+	if f.ServingGrams > 0 {
+		perServing = per100g.Scale(f.ServingGrams / 100)
+		carbs["perServing"] = perServing
+	}
+	if match, ok := glycemic.Lookup(f.Name, f.FdcID); ok {
+		gl := glycemic.Load(match.GI, per100g.Net)
+		estimate := map[string]interface{}{
+			"reference":            match.Reference,
+			"gi":                   match.GI,
+			"giCategory":           match.Category,
+			"matchedBy":            match.MatchedBy,
+			"glPer100g":            gl,
+			"glCategoryPer100g":    glycemic.LoadCategory(gl),
+			"glPerServing":         nil,
+			"glCategoryPerServing": nil,
+		}
+		if f.ServingGrams > 0 {
+			gl = glycemic.Load(match.GI, perServing.Net)
+			estimate["glPerServing"] = gl
+			estimate["glCategoryPerServing"] = glycemic.LoadCategory(gl)
+		}
+		carbs["glycemic"] = estimate
+	}
+	return carbs
+}
+
+// frontOfPackLabels computes the labels of the selected schemes. Whether
+// the food is a beverage follows the Nutri-Score classification.
+func frontOfPackLabels(f foodFacts, schemes []string) []frontofpack.Label {
+	category, _ := nutriscore.Classify(f.Name, f.Categories, f.Liquid)
+	return frontofpack.Compute(frontofpack.Input{
+		Amounts:    nutrients.Amounts(f.Per100g),
+		Beverage:   category == nutriscore.Beverage || category == nutriscore.Milk,
+		Sweeteners: nutriscore.HasSweeteners(f.Ingredients),
+	}, schemes)
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/Sush1sui/internal/frontofpack"
+)
+
+func TestFrontOfPackBeverages(t *testing.T) {
+	sugary := []map[string]interface{}{
+		{"id": "energy", "amount": 30.0, "unit": "kcal"},
+		{"id": "sugars", "amount": 6.0, "unit": "g"},
+	}
+	tests := []struct {
+		f        foodFacts
+		beverage bool
+	}{
+		{foodFacts{Name: "Tuna in water", Per100g: sugary}, false},
+		{foodFacts{Name: "Sardines in olive oil", Per100g: sugary}, false},
+		{foodFacts{Name: "Tea biscuits", Per100g: sugary}, false},
+		{foodFacts{Name: "Iced tea", Per100g: sugary, Liquid: true}, true},
+		{foodFacts{Name: "Chocolate milk", Per100g: sugary}, true},
+		{foodFacts{Name: "Tuna in water", Per100g: sugary, Categories: []string{"en:beverages"}}, true},
+	}
+	for _, tt := range tests {
+		// Chile warns of sugars above 10 g in foods but 5 g in beverages
+		warned := false
+		for _, l := range frontOfPackLabels(tt.f, []string{"chile"}) {
+			if l.Kind == frontofpack.Warning && l.ID == "high-sugars" {
+				warned = true
+			}
+		}
+		if warned != tt.beverage {
+			t.Errorf("%q with categories %v: sugar warning %v, want %v", tt.f.Name, tt.f.Categories, warned, tt.beverage)
+		}
+	}
+}
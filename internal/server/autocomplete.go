@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/suggest"
+)
+
+const maxSuggestions = 50
+
+// AutocompleteHandler suggests foods for a partially typed name from the
+// local index only, so it never waits on a provider.
+func AutocompleteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appkey := r.Header.Get("X-APP-KEY")
+	if appkey != config.Global.SUSHI_SECRET_KEY {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		http.Error(w, "No search query provided", http.StatusBadRequest)
+		return
+	}
+	limit := 10
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSuggestions {
+			http.Error(w, fmt.Sprintf("Invalid limit %q (1-%d)", v, maxSuggestions), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	results := suggestions.Suggest(q, limit)
+	if results == nil {
+		results = []suggest.Suggestion{}
+	}
+	resp := map[string]interface{}{
+		"message": "Suggestions retrieved successfully",
+		"data": map[string]interface{}{
+			"query":       q,
+			"suggestions": results,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// FoodHandler looks up a single food by the ID returned from search or
+// autocomplete, e.g. GET /v1/foods/usda:2345678.
+func FoodHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appkey := r.Header.Get("X-APP-KEY")
+	if appkey != config.Global.SUSHI_SECRET_KEY {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := nutritionOptionsFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/v1/foods/")
+	if id == "" {
+		http.Error(w, "No food id provided", http.StatusBadRequest)
+		return
+	}
+
+	product, err := lookupFoodByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	data := searchResult(product)
+	analyze(data, productFacts(product), opts)
+	resp := map[string]interface{}{
+		"message": "Food data received successfully",
+		"data":    data,
+	}
+	annotateNutrition(resp, opts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
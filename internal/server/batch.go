@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/health"
+)
+
+const (
+	maxBatchBarcodes = 100
+	// batchWorkers bounds the barcodes a batch looks up at once.
+	batchWorkers = 8
+)
+
+var errBatchCanceled = &statusError{http.StatusServiceUnavailable, "Request canceled"}
+
+// BarcodesBatchHandler looks up many barcodes through the lookup chain
+// behind BarcodeHandler. Every barcode gets an item with its data or error.
+// Items come back in request order, or with "format=ndjson" (or an Accept
+// of application/x-ndjson) one JSON line each as they resolve, followed by
+// a summary line.
+func BarcodesBatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appkey := r.Header.Get("X-APP-KEY")
+	if appkey != config.Global.SUSHI_SECRET_KEY {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := nutritionOptionsFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req struct {
+		Barcodes      []string        `json:"barcodes"`
+		HealthProfile *health.Profile `json:"healthProfile"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Barcodes) == 0 {
+		http.Error(w, "No barcodes provided", http.StatusBadRequest)
+		return
+	}
+	if len(req.Barcodes) > maxBatchBarcodes {
+		http.Error(w, fmt.Sprintf("At most %d barcodes can be looked up at once", maxBatchBarcodes), http.StatusBadRequest)
+		return
+	}
+	if err := opts.setHealth(req.HealthProfile); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	serveBatch(w, r, req.Barcodes, opts, lookupBarcode)
+}
+
+// serveBatch looks up codes with lookup and writes the items in the format
+// the request asks for.
+func serveBatch(w http.ResponseWriter, r *http.Request, codes []string, opts nutritionOptions, lookup func(context.Context, string) (*common.Product, error)) {
+	results := make(chan map[string]interface{})
+	go func() {
+		lookupBarcodes(r.Context(), codes, opts, lookup, results)
+		close(results)
+	}()
+
+	summary := map[string]interface{}{"total": len(codes), "resolved": 0, "failed": 0}
+	count := func(item map[string]interface{}) {
+		if item["status"] == "ok" {
+			summary["resolved"] = summary["resolved"].(int) + 1
+		} else {
+			summary["failed"] = summary["failed"].(int) + 1
+		}
+	}
+
+	if r.URL.Query().Get("format") == "ndjson" || strings.Contains(r.Header.Get("Accept"), "application/x-ndjson") {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+		for item := range results {
+			count(item)
+			annotateLists(item, opts)
+			enc.Encode(item)
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		enc.Encode(map[string]interface{}{
+			"message":     "Barcodes looked up",
+			"summary":     summary,
+			"dailyValues": opts.DV,
+			"profile":     opts.Profile.Name,
+		})
+		return
+	}
+
+	items := make([]map[string]interface{}, len(codes))
+	for item := range results {
+		count(item)
+		items[item["index"].(int)] = item
+	}
+	resp := map[string]interface{}{
+		"message": "Barcodes looked up",
+		"data": map[string]interface{}{
+			"items":   items,
+			"summary": summary,
+		},
+	}
+	annotateNutrition(resp, opts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// lookupBarcodes resolves each distinct barcode once, at most batchWorkers
+// at a time, and sends an item for every position it appears at. Barcodes
+// not started when ctx ends are reported as failed.
+func lookupBarcodes(ctx context.Context, codes []string, opts nutritionOptions, lookup func(context.Context, string) (*common.Product, error), out chan<- map[string]interface{}) {
+	positions := map[string][]int{}
+	var order []string
+	for i, code := range codes {
+		code = strings.TrimSpace(code)
+		if _, ok := positions[code]; !ok {
+			order = append(order, code)
+		}
+		positions[code] = append(positions[code], i)
+	}
+
+	sem := make(chan struct{}, batchWorkers)
+	var wg sync.WaitGroup
+	for _, code := range order {
+		wg.Add(1)
+		go func(code string) {
+			defer wg.Done()
+			var (
+				product *common.Product
+				err     error
+			)
+			select {
+			case sem <- struct{}{}:
+				// select picks at random when both are ready, so a slot
+				// can still be taken after ctx ends.
+				switch {
+				case ctx.Err() != nil:
+					err = errBatchCanceled
+				case code == "":
+					err = &statusError{http.StatusBadRequest, "No barcode data provided"}
+				default:
+					product, err = lookup(ctx, code)
+				}
+				<-sem
+			case <-ctx.Done():
+				err = errBatchCanceled
+			}
+			for _, i := range positions[code] {
+				out <- batchItem(i, code, product, err, opts)
+			}
+		}(code)
+	}
+	wg.Wait()
+}
+
+// batchItem renders one barcode's result: the data BarcodeHandler responds
+// with, or the error and its status.
+func batchItem(index int, code string, product *common.Product, err error, opts nutritionOptions) map[string]interface{} {
+	item := map[string]interface{}{"index": index, "barcode": code}
+	if err != nil {
+		status, message := http.StatusInternalServerError, err.Error()
+		if se, ok := err.(*statusError); ok {
+			status = se.status
+		}
+		item["status"] = "error"
+		item["statusCode"] = status
+		item["error"] = message
+		return item
+	}
+	data := product.Data()
+	analyze(data, productFacts(product), opts)
+	item["status"] = "ok"
+	item["message"] = barcodeMessages[product.Source]
+	item["data"] = data
+	return item
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+)
+
+func batchOptions(t *testing.T) nutritionOptions {
+	t.Helper()
+	config.Global = &config.Config{}
+	opts, err := nutritionOptionsFor(httptest.NewRequest(http.MethodPost, "/barcodes", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opts
+}
+
+func stubProduct(code string) *common.Product {
+	return &common.Product{
+		Source:    "openfoodfacts",
+		Barcode:   code,
+		Name:      "Product " + code,
+		Basis:     common.Per100g,
+		Nutrition: []map[string]interface{}{{"id": "energy", "amount": 100.0, "unit": "kcal"}},
+	}
+}
+
+// collect runs lookupBarcodes and returns its items by index.
+func collect(ctx context.Context, codes []string, opts nutritionOptions, lookup func(context.Context, string) (*common.Product, error)) map[int]map[string]interface{} {
+	out := make(chan map[string]interface{})
+	go func() {
+		lookupBarcodes(ctx, codes, opts, lookup, out)
+		close(out)
+	}()
+	items := map[int]map[string]interface{}{}
+	for item := range out {
+		items[item["index"].(int)] = item
+	}
+	return items
+}
+
+func TestLookupBarcodesBoundsWorkers(t *testing.T) {
+	opts := batchOptions(t)
+	var inFlight, peak int32
+	lookup := func(ctx context.Context, code string) (*common.Product, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return stubProduct(code), nil
+	}
+	codes := make([]string, 3*batchWorkers)
+	for i := range codes {
+		codes[i] = strconv.Itoa(i)
+	}
+	items := collect(context.Background(), codes, opts, lookup)
+	if len(items) != len(codes) {
+		t.Fatalf("got %d items, want %d", len(items), len(codes))
+	}
+	if peak > batchWorkers {
+		t.Errorf("%d lookups in flight, want at most %d", peak, batchWorkers)
+	}
+}
+
+func TestLookupBarcodesDuplicates(t *testing.T) {
+	opts := batchOptions(t)
+	var mu sync.Mutex
+	calls := map[string]int{}
+	lookup := func(ctx context.Context, code string) (*common.Product, error) {
+		mu.Lock()
+		calls[code]++
+		mu.Unlock()
+		return stubProduct(code), nil
+	}
+	items := collect(context.Background(), []string{"123", " 123 ", "456", "123"}, opts, lookup)
+	if calls["123"] != 1 || calls["456"] != 1 || len(calls) != 2 {
+		t.Errorf("lookups %v, want one per distinct barcode", calls)
+	}
+	for _, i := range []int{0, 1, 3} {
+		if items[i]["status"] != "ok" || items[i]["barcode"] != "123" {
+			t.Errorf("item %d = %v, want the 123 product", i, items[i])
+		}
+	}
+}
+
+func TestLookupBarcodesEmpty(t *testing.T) {
+	opts := batchOptions(t)
+	lookup := func(ctx context.Context, code string) (*common.Product, error) {
+		t.Errorf("looked up %q", code)
+		return nil, nil
+	}
+	items := collect(context.Background(), []string{"  "}, opts, lookup)
+	if items[0]["status"] != "error" || items[0]["statusCode"] != http.StatusBadRequest {
+		t.Errorf("empty barcode item = %v, want a 400 error", items[0])
+	}
+}
+
+func TestLookupBarcodesCanceled(t *testing.T) {
+	opts := batchOptions(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	lookup := func(ctx context.Context, code string) (*common.Product, error) {
+		t.Errorf("looked up %q after cancel", code)
+		return nil, nil
+	}
+	items := collect(ctx, []string{"1", "2", "3"}, opts, lookup)
+	for i := 0; i < 3; i++ {
+		if items[i]["statusCode"] != http.StatusServiceUnavailable {
+			t.Errorf("item %d = %v, want canceled", i, items[i])
+		}
+	}
+}
+
+func TestServeBatchJSON(t *testing.T) {
+	opts := batchOptions(t)
+	// later barcodes answer first
+	lookup := func(ctx context.Context, code string) (*common.Product, error) {
+		time.Sleep(time.Duration(3-len(code)) * 10 * time.Millisecond)
+		return stubProduct(code), nil
+	}
+	codes := []string{"1", "22", "333"}
+	w := httptest.NewRecorder()
+	serveBatch(w, httptest.NewRequest(http.MethodPost, "/barcodes", nil), codes, opts, lookup)
+
+	var resp struct {
+		Data struct {
+			Items []struct {
+				Index   int    `json:"index"`
+				Barcode string `json:"barcode"`
+			} `json:"items"`
+			Summary map[string]int `json:"summary"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.Items) != len(codes) {
+		t.Fatalf("got %d items, want %d", len(resp.Data.Items), len(codes))
+	}
+	for i, item := range resp.Data.Items {
+		if item.Index != i || item.Barcode != codes[i] {
+			t.Errorf("item %d = %+v, want barcode %q", i, item, codes[i])
+		}
+	}
+	if resp.Data.Summary["resolved"] != 3 {
+		t.Errorf("summary %v, want 3 resolved", resp.Data.Summary)
+	}
+}
+
+func TestServeBatchNDJSON(t *testing.T) {
+	opts := batchOptions(t)
+	lookup := func(ctx context.Context, code string) (*common.Product, error) {
+		return stubProduct(code), nil
+	}
+	w := httptest.NewRecorder()
+	serveBatch(w, httptest.NewRequest(http.MethodPost, "/barcodes?format=ndjson", nil), []string{"1", "2", ""}, opts, lookup)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type %q", ct)
+	}
+	var lines []map[string]interface{}
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 3 items and a summary", len(lines))
+	}
+	summary, _ := lines[3]["summary"].(map[string]interface{})
+	if summary["total"] != 3.0 || summary["resolved"] != 2.0 || summary["failed"] != 1.0 {
+		t.Errorf("summary %v, want 3 total, 2 resolved, 1 failed", summary)
+	}
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/compare"
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/health"
+	"github.com/Sush1sui/internal/nutrients"
+)
+
+const maxCompareProducts = 10
+
+// CompareHandler compares two or more products side by side. Barcodes go
+// through the lookup chain behind BarcodeHandler, FDC IDs and search IDs
+// through lookupFoodByID. Nutrients are aligned per 100 g and per serving
+// and judged by the comparison rules; each product's nutrition tables with
+// %DV, Nutri-Score, front-of-pack labels and health alerts are listed per
+// product.
+func CompareHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appkey := r.Header.Get("X-APP-KEY")
+	if appkey != config.Global.SUSHI_SECRET_KEY {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := nutritionOptionsFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req struct {
+		Barcodes      []string        `json:"barcodes"`
+		FdcIDs        []int           `json:"fdcIds"`
+		IDs           []string        `json:"ids"` // as returned by search and autocomplete
+		HealthProfile *health.Profile `json:"healthProfile"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := opts.setHealth(req.HealthProfile); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var ids []string
+	for _, code := range req.Barcodes {
+		ids = append(ids, "barcode:"+code)
+	}
+	for _, id := range req.FdcIDs {
+		ids = append(ids, "usda:"+strconv.Itoa(id))
+	}
+	ids = append(ids, req.IDs...)
+	if len(ids) < 2 {
+		http.Error(w, "At least two products are needed to compare", http.StatusBadRequest)
+		return
+	}
+	if len(ids) > maxCompareProducts {
+		http.Error(w, fmt.Sprintf("At most %d products can be compared", maxCompareProducts), http.StatusBadRequest)
+		return
+	}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			http.Error(w, fmt.Sprintf("Product %q is listed twice", id), http.StatusBadRequest)
+			return
+		}
+		seen[id] = true
+	}
+
+	products, err := lookupProducts(r.Context(), ids)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	entries := make([]map[string]interface{}, len(products))
+	sides := make([]compare.Product, len(products))
+	for i, p := range products {
+		f := productFacts(p)
+		per100g, perServing := p.DualNutrition()
+		sides[i] = compare.Product{
+			ID:         ids[i],
+			Per100g:    nutrients.Amounts(opts.Profile.Apply(per100g)),
+			PerServing: nutrients.Amounts(opts.Profile.Apply(perServing)),
+		}
+		entry := map[string]interface{}{
+			"id":            ids[i],
+			"source":        p.Source,
+			"name":          p.Name,
+			"brand":         p.Brand,
+			"servingSize":   p.ServingSize,
+			"servingWeight": nil,
+			"per100g":       nil,
+			"perServing":    nil,
+			"nutriScore":    nutriScoreFor(f, opts.NutriScore),
+			"labels":        frontOfPackLabels(f, opts.Labels),
+		}
+		if f.ServingGrams > 0 {
+			entry["servingWeight"] = common.RoundTo(f.ServingGrams, 1)
+		}
+		if per100g != nil {
+			entry["per100g"] = common.ChunkArray(per100g, 6)
+		}
+		if perServing != nil {
+			entry["perServing"] = common.ChunkArray(perServing, 6)
+		}
+		if opts.Health != nil {
+			entry["alerts"] = health.Alerts(*opts.Health, health.Input{
+				Name:         f.Name,
+				Ingredients:  f.Ingredients,
+				Amounts:      nutrients.Amounts(f.Per100g),
+				ServingGrams: f.ServingGrams,
+				FdcID:        f.FdcID,
+			})
+		}
+		entries[i] = entry
+	}
+
+	comparison := compare.Compare(sides, opts.Locale)
+	resp := map[string]interface{}{
+		"message": "Comparison computed successfully",
+		"data": map[string]interface{}{
+			"products":  entries,
+			"nutrients": comparison.Nutrients,
+			"wins":      comparison.Wins,
+		},
+	}
+	annotateNutrition(resp, opts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// lookupProducts resolves food IDs concurrently. The first failure, in ID
+// order, is returned with the ID it belongs to.
+func lookupProducts(ctx context.Context, ids []string) ([]*common.Product, error) {
+	products := make([]*common.Product, len(ids))
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			products[i], errs[i] = lookupFoodByID(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if se, ok := err.(*statusError); ok {
+			return nil, &statusError{se.status, fmt.Sprintf("%s: %s", ids[i], se.message)}
+		}
+		return nil, fmt.Errorf("%s: %w", ids[i], err)
+	}
+	return products, nil
+}
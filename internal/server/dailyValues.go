@@ -0,0 +1,168 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/Sush1sui/internal/allergens"
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/dailyvalue"
+	"github.com/Sush1sui/internal/diet"
+	"github.com/Sush1sui/internal/frontofpack"
+	"github.com/Sush1sui/internal/health"
+	"github.com/Sush1sui/internal/nutrients"
+	"github.com/Sush1sui/internal/nutriscore"
+)
+
+// nutritionKeys are the response fields holding nutrient lists.
+var nutritionKeys = map[string]bool{"nutrition": true, "per100g": true, "perServing": true}
+
+// nutritionOptions are the per-request settings for presenting nutrient
+// lists.
+type nutritionOptions struct {
+	DV      dailyvalue.Table
+	Profile nutrients.Profile
+	// Locale selects nutrient display names; see nutrients.Definition.Name.
+	Locale string
+	// NutriScore is the Nutri-Score algorithm version.
+	NutriScore string
+	// Labels are the front-of-pack label schemes to compute.
+	Labels []string
+	// Allergens limits allergen detection to these regulations.
+	Allergens []string
+	// Diets are the diets to check products against.
+	Diets []string
+	// Health is the user's health profile from the request body, nil when
+	// none was sent.
+	Health *health.Profile
+}
+
+// nutritionOptionsFor reads the %DV reference table from the "dv" (set)
+// and "dvGroup" query parameters, the nutrient profile from "profile" with
+// its "zeros" and "missing" policy overrides, the display name locale from
+// "locale", the Nutri-Score algorithm from "nutriscore" and the
+// comma-separated front-of-pack label schemes from "labels", allergen
+// regulations from "allergens" and diets from "diets".
+func nutritionOptionsFor(r *http.Request) (nutritionOptions, error) {
+	q := r.URL.Query()
+	t, err := dailyvalue.Select(q.Get("dv"), q.Get("dvGroup"))
+	if err != nil {
+		return nutritionOptions{}, &statusError{http.StatusBadRequest, err.Error()}
+	}
+	name := q.Get("profile")
+	if name == "" {
+		name = config.Global.NUTRIENT_PROFILE
+	}
+	profile, err := nutrients.SelectProfile(name, q.Get("zeros"), q.Get("missing"))
+	if err != nil {
+		return nutritionOptions{}, &statusError{http.StatusBadRequest, err.Error()}
+	}
+	locale := q.Get("locale")
+	if locale == "" {
+		locale = nutrients.DefaultLocale
+	}
+	version := q.Get("nutriscore")
+	if version == "" {
+		version = nutriscore.DefaultVersion
+	}
+	if err := nutriscore.CheckVersion(version); err != nil {
+		return nutritionOptions{}, &statusError{http.StatusBadRequest, err.Error()}
+	}
+	labels, err := frontofpack.Schemes(splitList(q.Get("labels")))
+	if err != nil {
+		return nutritionOptions{}, &statusError{http.StatusBadRequest, err.Error()}
+	}
+	regulations := splitList(q.Get("allergens"))
+	if err := allergens.CheckRegulations(regulations); err != nil {
+		return nutritionOptions{}, &statusError{http.StatusBadRequest, err.Error()}
+	}
+	diets, err := diet.Select(splitList(q.Get("diets")))
+	if err != nil {
+		return nutritionOptions{}, &statusError{http.StatusBadRequest, err.Error()}
+	}
+	return nutritionOptions{
+		DV:         t,
+		Profile:    profile,
+		Locale:     locale,
+		NutriScore: version,
+		Labels:     labels,
+		Allergens:  regulations,
+		Diets:      diets,
+	}, nil
+}
+
+// setHealth validates the health profile sent in a request body and uses
+// it for alerts. A nil profile leaves the options without one.
+func (opts *nutritionOptions) setHealth(p *health.Profile) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.Check(); err != nil {
+		return &statusError{http.StatusBadRequest, err.Error()}
+	}
+	opts.Health = p
+	return nil
+}
+
+// annotateNutrition applies the nutrient profile to every nutrient list in
+// a response, adds %DV, renames registry nutrients for the requested locale
+// and records which profile and reference table were used.
+func annotateNutrition(resp map[string]interface{}, opts nutritionOptions) {
+	annotateLists(resp, opts)
+	resp["dailyValues"] = opts.DV
+	resp["profile"] = opts.Profile.Name
+}
+
+// annotateLists is annotateNutrition without the record of the profile and
+// table, for parts of a response sent separately.
+func annotateLists(v interface{}, opts nutritionOptions) {
+	walkNutrition(v, func(list []map[string]interface{}) []map[string]interface{} {
+		list = opts.Profile.Apply(list)
+		opts.DV.Annotate(list)
+		localizeNutrition(list, opts.Locale)
+		return list
+	})
+}
+
+// localizeNutrition replaces the display names of registry nutrients.
+func localizeNutrition(list []map[string]interface{}, locale string) {
+	if locale == nutrients.DefaultLocale {
+		return
+	}
+	for _, n := range list {
+		id, _ := n["id"].(string)
+		if def, ok := nutrients.ByID(id); ok {
+			n["name"] = def.Name(locale)
+		}
+	}
+}
+
+// walkNutrition replaces every nutrient list found under nutritionKeys
+// anywhere in v with the result of visit. Chunked lists are visited whole
+// and chunked again, since a profile can reorder across chunks.
+func walkNutrition(v interface{}, visit func([]map[string]interface{}) []map[string]interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if nutritionKeys[k] {
+				switch list := child.(type) {
+				case [][]map[string]interface{}:
+					var flat []map[string]interface{}
+					for _, chunk := range list {
+						flat = append(flat, chunk...)
+					}
+					v[k] = common.ChunkArray(visit(flat), 6)
+					continue
+				case []map[string]interface{}:
+					v[k] = visit(list)
+					continue
+				}
+			}
+			walkNutrition(child, visit)
+		}
+	case []map[string]interface{}:
+		for _, child := range v {
+			walkNutrition(child, visit)
+		}
+	}
+}
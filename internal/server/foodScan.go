@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/vision"
+)
+
+// statusError carries the HTTP status a lookup failure should be reported with.
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string { return e.message }
+
+// writeError reports err to the client, using its status when it has one.
+func writeError(w http.ResponseWriter, err error) {
+	if se, ok := err.(*statusError); ok {
+		http.Error(w, se.message, se.status)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+type foodPrediction struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// classifyFood sends an image to the Hugging Face food classifier and returns
+// its top prediction.
+func classifyFood(ctx context.Context, img []byte) (foodPrediction, error) {
+	hfReq, _ := http.NewRequestWithContext(ctx, "POST", "https://api-inference.huggingface.co/models/nateraw/food", bytes.NewReader(img))
+	hfReq.Header.Set("Authorization", "Bearer "+config.Global.HUGGINGFACE_API_KEY)
+	hfReq.Header.Set("Content-Type", "application/octet-stream")
+	hfResp, err := http.DefaultClient.Do(hfReq)
+	if err != nil {
+		fmt.Printf("FoodScanHandler: Hugging Face API error: %v\n", err)
+		return foodPrediction{}, &statusError{http.StatusInternalServerError, "Failed to fetch data from Hugging Face: " + err.Error()}
+	}
+	defer hfResp.Body.Close()
+	if hfResp.StatusCode != 200 {
+		body, _ := io.ReadAll(hfResp.Body)
+		fmt.Printf("FoodScanHandler: Hugging Face API error: status %d, body: %s\n", hfResp.StatusCode, string(body))
+		return foodPrediction{}, &statusError{http.StatusInternalServerError, "Failed to fetch data from Hugging Face: " + string(body)}
+	}
+
+	var predictions []foodPrediction
+	if err := json.NewDecoder(hfResp.Body).Decode(&predictions); err != nil || len(predictions) == 0 || predictions[0].Score < 0.5 {
+		return foodPrediction{}, &statusError{http.StatusNotFound, "No food items detected in the image"}
+	}
+	fmt.Printf("FoodScanHandler: Prediction label: %s, score: %f\n", predictions[0].Label, predictions[0].Score)
+	return predictions[0], nil
+}
+
+// lookupScannedFood queries USDA for a classifier label. Nutrition comes from
+// the first Survey (FNDDS) food scaled to the requested portion, ingredients
+// from the first Branded food; each is reported as soon as it is known. The
+// scaled nutrients are returned as well so callers can total several foods.
+func lookupScannedFood(ctx context.Context, label string, portionIn *common.PortionInput, report stage) (map[string]interface{}, []common.Nutrient, error) {
+	foods, err := searchUSDA(ctx, label, []string{"Survey (FNDDS)", "Branded"}, 1, 0)
+	if err != nil {
+		fmt.Printf("FoodScanHandler: USDA API error: %v\n", err)
+		return nil, nil, &statusError{http.StatusInternalServerError, "Failed to fetch data from USDA API"}
+	}
+	fmt.Printf("FoodScanHandler: USDA foods found: %d\n", len(foods))
+
+	results := map[string]interface{}{
+		"foodName": label,
+	}
+	var scaled []common.Nutrient
+
+	// get nutrition from first Survey (FNDDS) food, scaled to the requested portion
+	for _, f := range foods {
+		if f.DataType == "Survey (FNDDS)" {
+			portions, err := fetchFoodPortions(ctx, f.FdcID)
+			if err != nil {
+				fmt.Printf("FoodScanHandler: USDA portions error: %v\n", err)
+			}
+			portion, err := common.ResolvePortion(portions, portionIn)
+			if err != nil {
+				return nil, nil, &statusError{http.StatusBadRequest, err.Error()}
+			}
+
+			scaled = common.ScaleNutrients(f.FoodNutrients, portion.GramWeight)
+			per100g, perServing := common.DualBasis(common.NormalizeNutrients(f.FoodNutrients), common.Per100g, portion.GramWeight)
+			nutrition := common.ChunkArray(perServing, 6)
+			for i := range nutrition {
+				chunked := common.ChunkArray(nutrition[i], 2)
+				flat := []map[string]any{}
+				for _, arr := range chunked {
+					flat = append(flat, arr...)
+				}
+				nutrition[i] = flat
+			}
+			results["fdcId"] = f.FdcID
+			// nutrition is the portion's, as before per-100 g amounts were
+			// added; it is kept for existing clients
+			results["nutrition"] = nutrition
+			results["perServing"] = nutrition
+			results["per100g"] = common.ChunkArray(per100g, 6)
+			results["servingWeight"] = common.RoundTo(portion.GramWeight, 1)
+			results["servingSize"] = portion.Label()
+			results["serving"], _ = common.ParseServingSize(portion.Label())
+			results["portion"] = portion
+			results["portions"] = portions
+			break
+		}
+	}
+	// a requested portion cannot be served from a Branded food
+	if _, ok := results["portion"]; !ok && portionIn != nil {
+		return nil, nil, &statusError{http.StatusUnprocessableEntity, "No portion data for this food"}
+	}
+	report("nutrition", pick(results, nutritionFields))
+
+	// get ingredients from first Branded food; its serving size belongs to
+	// a different product, so it is only used when no FNDDS portion exists
+	for _, f := range foods {
+		if f.DataType == "Branded" && (f.PackageWeight != "" || (f.ServingSize > 0 && f.ServingSizeUnit != "")) && f.Ingredients != "" {
+			results["ingredients"] = f.Ingredients
+			if _, ok := results["servingSize"]; !ok {
+				if f.PackageWeight != "" {
+					results["servingSize"] = f.PackageWeight
+				} else {
+					results["servingSize"] = fmt.Sprintf("%v%v", f.ServingSize, f.ServingSizeUnit)
+				}
+			}
+			break
+		}
+	}
+	report("ingredients", map[string]interface{}{"ingredients": results["ingredients"]})
+	return results, scaled, nil
+}
+
+// scanPlate detects the separate foods on a plate, classifies and looks up
+// each region, and totals the nutrition of the whole meal. Every item uses
+// its default FNDDS portion.
+func scanPlate(ctx context.Context, w http.ResponseWriter, img []byte, opts nutritionOptions) {
+	detector := vision.NewDetector(config.Global.DETECTION_BACKEND, config.Global.DETECTION_MODEL, config.Global.HUGGINGFACE_API_KEY)
+	regions, err := detector.Detect(img)
+	if err == nil && len(regions) == 0 {
+		// nothing food-like was detected, classify the plate as a whole
+		regions, err = vision.StubDetector{}.Detect(img)
+	}
+	if errors.Is(err, vision.ErrInvalidImage) {
+		http.Error(w, "Invalid image format", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		fmt.Printf("FoodScanHandler: detection error: %v\n", err)
+		http.Error(w, "Failed to detect food items", http.StatusInternalServerError)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	var totals [][]common.Nutrient
+	// failures of Hugging Face or USDA, as opposed to regions without food
+	upstreamFailures := 0
+	for _, region := range regions {
+		prediction, err := classifyFood(ctx, region.Image)
+		if err != nil {
+			fmt.Printf("FoodScanHandler: region %v skipped: %v\n", region.Box, err)
+			if upstreamFailed(err) {
+				upstreamFailures++
+			}
+			continue
+		}
+		item, nutrients, err := lookupScannedFood(ctx, prediction.Label, nil, noStages)
+		if err != nil {
+			fmt.Printf("FoodScanHandler: region %v lookup failed: %v\n", region.Box, err)
+			if upstreamFailed(err) {
+				upstreamFailures++
+			}
+			continue
+		}
+		item["score"] = prediction.Score
+		analyze(item, scannedFacts(item), opts)
+		item["box"] = region.Box
+		items = append(items, item)
+		totals = append(totals, nutrients)
+	}
+	if len(items) == 0 && len(regions) > 0 && upstreamFailures == len(regions) {
+		http.Error(w, "Failed to classify or look up the food items", http.StatusBadGateway)
+		return
+	}
+	if len(items) == 0 {
+		http.Error(w, "No food items detected in the image", http.StatusNotFound)
+		return
+	}
+
+	total := common.SumNutrients(totals...)
+	resp := map[string]interface{}{
+		"message": "Plate scan data received successfully",
+		"data": map[string]interface{}{
+			"items": items,
+			"total": map[string]interface{}{
+				"nutrition": common.ChunkArray(common.NormalizeNutrients(total), 6),
+			},
+		},
+	}
+	annotateNutrition(resp, opts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// upstreamFailed reports whether err is a failure of a provider rather than
+// a finding, such as no food in a region.
+func upstreamFailed(err error) bool {
+	se, ok := err.(*statusError)
+	return !ok || se.status >= http.StatusInternalServerError
+}
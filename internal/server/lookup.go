@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Sush1sui/internal/common"
+)
+
+// barcodeMessages is the response message for each source of the barcode
+// lookup chain.
+var barcodeMessages = map[string]string{
+	"usda":          "Barcode data received successfully",
+	"nutritionix":   "Barcode data received successfully from Nutritionix",
+	"openfoodfacts": "Barcode data received successfully from Open Food Facts",
+}
+
+// lookupBarcode resolves a barcode through the product cache, then USDA,
+// then Nutritionix, and uses Open Food Facts as a last resort.
+func lookupBarcode(ctx context.Context, code string) (*common.Product, error) {
+	if id, ok := barcodeCache.Get(code); ok {
+		if p, ok := productCache.Get(id); ok {
+			return p, nil
+		}
+	}
+
+	p, err := fetchBarcode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	rememberProduct(p, 1)
+	barcodeCache.Set(code, p.ID)
+	return p, nil
+}
+
+func fetchBarcode(ctx context.Context, code string) (*common.Product, error) {
+	// USDA API
+	if foods, err := searchUSDA(ctx, code, nil, 1, 0); err == nil && len(foods) > 0 {
+		return foods[0].product(), nil
+	}
+
+	// Nutritionix Fallback
+	if foods, err := fetchNutritionixItem(ctx, code); err == nil && len(foods) > 0 {
+		p := foods[0].product()
+		p.Barcode = code
+		return p, nil
+	}
+
+	// If both APIs fail
+	// Use open food facts as a last resort
+	off, err := fetchOFFProduct(ctx, code)
+	if err != nil {
+		fmt.Printf("lookupBarcode: Open Food Facts error: %v\n", err)
+		return nil, &statusError{http.StatusInternalServerError, "Failed to fetch data."}
+	}
+	if off.ProductName == "" {
+		return nil, &statusError{http.StatusNotFound, "No product found for the barcode"}
+	}
+	return off.product(), nil
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/nutrients"
+)
+
+// nutritionixFood is a food as returned by the Nutritionix item and search
+// endpoints.
+type nutritionixFood struct {
+	FoodName              string  `json:"food_name"`
+	BrandName             string  `json:"brand_name"`
+	NixItemID             string  `json:"nix_item_id"`
+	NfIngredientStatement string  `json:"nf_ingredient_statement"`
+	ServingQty            float64 `json:"serving_qty"`
+	ServingUnit           string  `json:"serving_unit"`
+	ServingWeightGrams    float64 `json:"serving_weight_grams"`
+	FullNutrients         []struct {
+		AttrID int     `json:"attr_id"`
+		Value  float64 `json:"value"`
+	} `json:"full_nutrients"`
+}
+
+// nutrients returns the reported nutrients the registry knows, keyed by
+// their attr_id.
+func (f nutritionixFood) nutrients() []common.Nutrient {
+	var list []common.Nutrient
+	for _, n := range f.FullNutrients {
+		if def, unit, ok := nutrients.ByNutritionixAttrID(n.AttrID); ok {
+			list = append(list, common.Nutrient{
+				ID:           def.ID,
+				NutrientName: def.Name(nutrients.DefaultLocale),
+				Value:        n.Value,
+				UnitName:     unit,
+			})
+		}
+	}
+	return list
+}
+
+func (f nutritionixFood) product() *common.Product {
+	servingSize := "N/A"
+	if f.ServingQty > 0 && f.ServingUnit != "" && f.ServingWeightGrams > 0 {
+		servingSize = fmt.Sprintf("%v %v (%.0fg)", f.ServingQty, f.ServingUnit, f.ServingWeightGrams)
+	}
+
+	id := "nutritionix:" + f.NixItemID
+	if f.NixItemID == "" {
+		id = "nutritionix:" + f.FoodName
+	}
+	return &common.Product{
+		ID:           id,
+		Source:       "nutritionix",
+		Name:         f.FoodName,
+		Brand:        f.BrandName,
+		Ingredients:  f.NfIngredientStatement,
+		ServingSize:  servingSize,
+		ServingGrams: f.ServingWeightGrams,
+		Basis:        common.PerServing,
+		Nutrition:    common.NormalizeNutrients(f.nutrients()),
+	}
+}
+
+// nutritionixGet calls a Nutritionix v2 endpoint and decodes its JSON body.
+func nutritionixGet(ctx context.Context, path string, params url.Values, out interface{}) error {
+	nutriReq, _ := http.NewRequestWithContext(ctx, "GET", "https://trackapi.nutritionix.com/v2/"+path+"?"+params.Encode(), nil)
+	nutriReq.Header.Set("x-app-id", config.Global.NUTRITIONIX_APP_ID)
+	nutriReq.Header.Set("x-app-key", config.Global.NUTRITIONIX_API_KEY)
+	resp, err := http.DefaultClient.Do(nutriReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Nutritionix %s returned status %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchNutritionixItem looks up a branded item by UPC.
+func fetchNutritionixItem(ctx context.Context, upc string) ([]nutritionixFood, error) {
+	var data struct {
+		Foods []nutritionixFood `json:"foods"`
+	}
+	err := nutritionixGet(ctx, "search/item", url.Values{"upc": {upc}}, &data)
+	return data.Foods, err
+}
+
+// searchNutritionix runs a detailed instant search, which includes full
+// nutrients for both common and branded foods.
+func searchNutritionix(ctx context.Context, query string) ([]nutritionixFood, error) {
+	var data struct {
+		Common  []nutritionixFood `json:"common"`
+		Branded []nutritionixFood `json:"branded"`
+	}
+	err := nutritionixGet(ctx, "search/instant", url.Values{"query": {query}, "detailed": {"true"}}, &data)
+	return append(data.Common, data.Branded...), err
+}
+
+// fetchNutritionixItemByID looks up a branded item by its nix_item_id.
+func fetchNutritionixItemByID(ctx context.Context, id string) ([]nutritionixFood, error) {
+	var data struct {
+		Foods []nutritionixFood `json:"foods"`
+	}
+	err := nutritionixGet(ctx, "search/item", url.Values{"nix_item_id": {id}}, &data)
+	return data.Foods, err
+}
+
+// fetchNutritionixNatural resolves free text such as "1 cup rice" through
+// the natural language nutrients endpoint.
+func fetchNutritionixNatural(ctx context.Context, query string) ([]nutritionixFood, error) {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	nutriReq, _ := http.NewRequestWithContext(ctx, "POST", "https://trackapi.nutritionix.com/v2/natural/nutrients", bytes.NewReader(body))
+	nutriReq.Header.Set("x-app-id", config.Global.NUTRITIONIX_APP_ID)
+	nutriReq.Header.Set("x-app-key", config.Global.NUTRITIONIX_API_KEY)
+	nutriReq.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(nutriReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Nutritionix natural/nutrients returned status %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Foods []nutritionixFood `json:"foods"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	return data.Foods, err
+}
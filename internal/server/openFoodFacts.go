@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/units"
+)
+
+const offUserAgent = "nutrisight-thesis/1.0 - (github.com/Sush1sui)"
+
+// offProduct is a product as returned by the Open Food Facts API.
+type offProduct struct {
+	Code            string                 `json:"code"`
+	ProductName     string                 `json:"product_name"`
+	Brands          string                 `json:"brands"`
+	IngredientsText string                 `json:"ingredients_text"`
+	Nutriments      map[string]interface{} `json:"nutriments"`
+	ServingSize     string                 `json:"serving_size"`
+	// nutrition_data_per is the basis of the plain nutriment keys, "100g" or
+	// "serving"
+	NutritionDataPer string `json:"nutrition_data_per"`
+	// serving_quantity is a number or a numeric string depending on the product
+	ServingQuantity     interface{} `json:"serving_quantity"`
+	ServingQuantityUnit string      `json:"serving_quantity_unit"`
+	// certification labels and OFF's own vegan/vegetarian analysis
+	LabelsTags              []string `json:"labels_tags"`
+	IngredientsAnalysisTags []string `json:"ingredients_analysis_tags"`
+	// nova_group is a number or a numeric string depending on the endpoint
+	NovaGroup interface{} `json:"nova_group"`
+	// categories_tags run from the broadest category to the most specific
+	CategoriesTags []string `json:"categories_tags"`
+}
+
+// servingGrams reads serving_quantity, which OFF gives in grams unless
+// serving_quantity_unit says ml.
+func (p offProduct) servingGrams() float64 {
+	var qty float64
+	switch v := p.ServingQuantity.(type) {
+	case float64:
+		qty = v
+	case string:
+		qty, _ = strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	unit := p.ServingQuantityUnit
+	if unit == "" {
+		unit = units.Gram
+	}
+	grams, _ := units.ServingGrams(qty, unit)
+	return grams
+}
+
+// novaGroup reads nova_group, 0 when the product has none.
+func (p offProduct) novaGroup() int {
+	switch v := p.NovaGroup.(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(v))
+		return n
+	}
+	return 0
+}
+
+// product converts p, keeping per-100g nutrition as reported and the
+// per-serving values next to it. Products labelled per serving only are
+// given on that basis.
+func (p offProduct) product() *common.Product {
+	basis := common.Per100g
+	nutrition := common.FormatNutriments(p.Nutriments, common.Per100g, p.NutritionDataPer)
+	perServing := common.FormatNutriments(p.Nutriments, common.PerServing, p.NutritionDataPer)
+	if len(nutrition) == 0 && len(perServing) > 0 {
+		basis, nutrition, perServing = common.PerServing, perServing, nil
+	}
+	return &common.Product{
+		ID:               "off:" + p.Code,
+		Source:           "openfoodfacts",
+		Barcode:          p.Code,
+		Name:             p.ProductName,
+		Brand:            p.Brands,
+		Ingredients:      p.IngredientsText,
+		ServingSize:      p.ServingSize,
+		ServingGrams:     p.servingGrams(),
+		Basis:            basis,
+		Nutrition:        nutrition,
+		ServingNutrition: perServing,
+		LabelTags:        p.LabelsTags,
+		AnalysisTags:     p.IngredientsAnalysisTags,
+		NovaGroup:        p.novaGroup(),
+		Category:         p.category(),
+		Categories:       p.CategoriesTags,
+	}
+}
+
+// category is the most specific category tag, "" when there is none.
+func (p offProduct) category() string {
+	if len(p.CategoriesTags) == 0 {
+		return ""
+	}
+	return p.CategoriesTags[len(p.CategoriesTags)-1]
+}
+
+// offGet calls the Open Food Facts API and decodes its JSON body.
+func offGet(ctx context.Context, offURL string, out interface{}) error {
+	offReq, _ := http.NewRequestWithContext(ctx, "GET", offURL, nil)
+	offReq.Header.Set("User-Agent", offUserAgent)
+	resp, err := http.DefaultClient.Do(offReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Open Food Facts returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchOFFProduct looks up a product by barcode.
+func fetchOFFProduct(ctx context.Context, code string) (offProduct, error) {
+	var data struct {
+		Product offProduct `json:"product"`
+	}
+	err := offGet(ctx, fmt.Sprintf("https://world.openfoodfacts.net/api/v2/product/%s.json", code), &data)
+	if data.Product.Code == "" {
+		data.Product.Code = code
+	}
+	return data.Product, err
+}
+
+// searchOFF runs a full-text product search.
+func searchOFF(ctx context.Context, query string, page, pageSize int) ([]offProduct, error) {
+	params := url.Values{}
+	params.Set("search_terms", query)
+	params.Set("search_simple", "1")
+	params.Set("action", "process")
+	params.Set("json", "1")
+	params.Set("page", strconv.Itoa(page))
+	params.Set("page_size", strconv.Itoa(pageSize))
+
+	var data struct {
+		Products []offProduct `json:"products"`
+	}
+	err := offGet(ctx, "https://world.openfoodfacts.org/cgi/search.pl?"+params.Encode(), &data)
+	return data.Products, err
+}
+
+// searchOFFCategory lists the most scanned products of a category tag.
+func searchOFFCategory(ctx context.Context, tag string, pageSize int) ([]offProduct, error) {
+	params := url.Values{}
+	params.Set("categories_tags", tag)
+	params.Set("sort_by", "unique_scans_n")
+	params.Set("page_size", strconv.Itoa(pageSize))
+
+	var data struct {
+		Products []offProduct `json:"products"`
+	}
+	err := offGet(ctx, "https://world.openfoodfacts.org/api/v2/search?"+params.Encode(), &data)
+	return data.Products, err
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+)
+
+// ParseMealHandler turns a typed meal such as "2 eggs and a slice of toast"
+// into per-item and total nutrition. Items are parsed locally and resolved
+// through the USDA lookup used by FoodScanHandler; provider "nutritionix"
+// hands the whole text to Nutritionix natural/nutrients instead.
+func ParseMealHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appkey := r.Header.Get("X-APP-KEY")
+	if appkey != config.Global.SUSHI_SECRET_KEY {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := nutritionOptionsFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req struct {
+		Text     string `json:"text"`
+		Provider string `json:"provider"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		http.Error(w, "No meal text provided", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		items      []map[string]interface{}
+		unresolved []map[string]interface{}
+		totals     [][]common.Nutrient
+	)
+	switch req.Provider {
+	case "", "usda":
+		req.Provider = "usda"
+		parsed := common.ParseMeal(req.Text, suggestions.Has)
+		if len(parsed) == 0 {
+			http.Error(w, "No food items found in the text", http.StatusBadRequest)
+			return
+		}
+		items, unresolved, totals = resolveMealItems(r.Context(), parsed)
+	case "nutritionix":
+		foods, err := fetchNutritionixNatural(r.Context(), req.Text)
+		if err != nil {
+			fmt.Printf("ParseMealHandler: Nutritionix error: %v\n", err)
+			http.Error(w, "Failed to fetch data from Nutritionix", http.StatusBadGateway)
+			return
+		}
+		for _, f := range foods {
+			item := f.product().Data()
+			item["text"] = strings.TrimSpace(fmt.Sprintf("%v %s %s", f.ServingQty, f.ServingUnit, f.FoodName))
+			item["quantity"] = f.ServingQty
+			item["unit"] = f.ServingUnit
+			item["food"] = f.FoodName
+			item["foodName"] = f.FoodName
+			items = append(items, item)
+			totals = append(totals, f.nutrients())
+		}
+	default:
+		http.Error(w, fmt.Sprintf("Unknown provider %q", req.Provider), http.StatusBadRequest)
+		return
+	}
+
+	if len(items) == 0 {
+		http.Error(w, "No food items could be resolved", http.StatusNotFound)
+		return
+	}
+	if unresolved == nil {
+		unresolved = []map[string]interface{}{}
+	}
+
+	total := common.SumNutrients(totals...)
+	resp := map[string]interface{}{
+		"message": "Meal parsed successfully",
+		"data": map[string]interface{}{
+			"text":       req.Text,
+			"provider":   req.Provider,
+			"items":      items,
+			"unresolved": unresolved,
+			"total": map[string]interface{}{
+				"nutrition": common.ChunkArray(common.NormalizeNutrients(total), 6),
+			},
+		},
+	}
+	annotateNutrition(resp, opts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// resolveMealItems looks up every parsed item concurrently, keeping the
+// order they were typed in.
+func resolveMealItems(ctx context.Context, parsed []common.MealItem) ([]map[string]interface{}, []map[string]interface{}, [][]common.Nutrient) {
+	results := make([]map[string]interface{}, len(parsed))
+	nutrients := make([][]common.Nutrient, len(parsed))
+	errs := make([]error, len(parsed))
+
+	var wg sync.WaitGroup
+	for i, item := range parsed {
+		wg.Add(1)
+		go func(i int, item common.MealItem) {
+			defer wg.Done()
+			results[i], nutrients[i], errs[i] = lookupMealItem(ctx, item)
+		}(i, item)
+	}
+	wg.Wait()
+
+	var items, unresolved []map[string]interface{}
+	var totals [][]common.Nutrient
+	for i, item := range parsed {
+		entry := map[string]interface{}{
+			"text":     item.Text,
+			"quantity": item.Quantity,
+			"unit":     item.Unit,
+			"food":     item.Food,
+		}
+		if errs[i] == nil && results[i]["nutrition"] == nil {
+			errs[i] = fmt.Errorf("no nutrition data found")
+		}
+		if errs[i] != nil {
+			entry["error"] = errs[i].Error()
+			unresolved = append(unresolved, entry)
+			continue
+		}
+		for k, v := range results[i] {
+			entry[k] = v
+		}
+		items = append(items, entry)
+		totals = append(totals, nutrients[i])
+	}
+	return items, unresolved, totals
+}
+
+// lookupMealItem resolves a parsed item through lookupScannedFood. A count
+// word the food has no portion for, such as "2 bowl", falls back to that
+// many default portions; weights and volumes always convert.
+func lookupMealItem(ctx context.Context, item common.MealItem) (map[string]interface{}, []common.Nutrient, error) {
+	res, n, err := lookupScannedFood(ctx, item.Food, &common.PortionInput{Measure: item.Measure()}, noStages)
+	if se, ok := err.(*statusError); ok && se.status == http.StatusBadRequest && item.Countable() {
+		res, n, err = lookupScannedFood(ctx, item.Food, &common.PortionInput{Measure: fmt.Sprint(item.Quantity)}, noStages)
+		if err == nil {
+			res["note"] = fmt.Sprintf("No %q portion for this food, used %v default portions", item.Unit, item.Quantity)
+		}
+	}
+	return res, n, err
+}
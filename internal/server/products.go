@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sush1sui/internal/cache"
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/suggest"
+)
+
+var (
+	// productTTL is how long products stay cached and suggestable.
+	productTTL = 24 * time.Hour
+	// productCache holds every product a provider returned, keyed by ID.
+	productCache = cache.New[*common.Product](productTTL)
+	// barcodeCache maps looked-up barcodes to product IDs.
+	barcodeCache = cache.New[string](productTTL)
+	// suggestions is the autocomplete index over cached products, classifier
+	// labels and the imported food list.
+	suggestions = suggest.NewIndex()
+)
+
+// initProductStore sizes the caches and seeds the autocomplete index.
+func initProductStore(ttl time.Duration, foodListPath string) {
+	productTTL = ttl
+	productCache = cache.New[*common.Product](ttl)
+	barcodeCache = cache.New[string](ttl)
+	suggestions = suggest.NewIndex()
+
+	for _, label := range food101Labels {
+		name := strings.ReplaceAll(label, "_", " ")
+		suggestions.Add(suggest.Entry{
+			ID:     "label:" + label,
+			Name:   strings.ToUpper(name[:1]) + name[1:],
+			Source: "classifier",
+		})
+	}
+
+	if foodListPath != "" {
+		entries, err := suggest.LoadFoodList(foodListPath)
+		if err != nil {
+			fmt.Println("Error loading food list:", err)
+		}
+		for _, e := range entries {
+			suggestions.Add(e)
+		}
+	}
+	fmt.Printf("Autocomplete index ready with %d foods\n", suggestions.Len())
+}
+
+// rememberProduct caches a product and makes it suggestable for as long as
+// it is cached. Weight counts as one lookup; search results that were
+// merely listed pass 0.
+func rememberProduct(p *common.Product, weight float64) {
+	if p == nil || p.ID == "" {
+		return
+	}
+	cacheProduct(p)
+	suggestions.Add(suggest.Entry{
+		ID:      p.ID,
+		Name:    p.Name,
+		Brand:   p.Brand,
+		Source:  p.Source,
+		Weight:  weight,
+		Expires: time.Now().Add(productTTL),
+	})
+}
+
+// cacheProduct caches a product without making it suggestable.
+func cacheProduct(p *common.Product) {
+	if p == nil || p.ID == "" {
+		return
+	}
+	productCache.Set(p.ID, p)
+	if p.Barcode != "" {
+		barcodeCache.Set(p.Barcode, p.ID)
+	}
+}
+
+// cachedProducts returns every cached product once, although
+// lookupFoodByID also caches products under the IDs they were asked by.
+func cachedProducts() []*common.Product {
+	seen := map[*common.Product]bool{}
+	var out []*common.Product
+	for _, p := range productCache.Values() {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// lookupFoodByID resolves the IDs returned by search and autocomplete:
+// "usda:<fdcId>", "off:<barcode>", "nutritionix:<nix_item_id or name>",
+// "label:<classifier label>", "barcode:<code>" or a bare barcode.
+func lookupFoodByID(ctx context.Context, id string) (*common.Product, error) {
+	if p, ok := productCache.Get(id); ok {
+		return p, nil
+	}
+
+	source, key, _ := strings.Cut(id, ":")
+	var p *common.Product
+	switch source {
+	case "usda":
+		fdcID, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, &statusError{http.StatusBadRequest, fmt.Sprintf("Invalid USDA id %q", id)}
+		}
+		food, _, err := fetchUSDAFood(ctx, fdcID)
+		if err != nil {
+			fmt.Printf("lookupFoodByID: USDA error: %v\n", err)
+			return nil, &statusError{http.StatusNotFound, "No food found for " + id}
+		}
+		p = food.product()
+	case "off":
+		off, err := fetchOFFProduct(ctx, key)
+		if err != nil || off.ProductName == "" {
+			return nil, &statusError{http.StatusNotFound, "No food found for " + id}
+		}
+		p = off.product()
+	case "nutritionix":
+		foods, err := fetchNutritionixItemByID(ctx, key)
+		if err != nil || len(foods) == 0 {
+			// common foods have no item id, only a name
+			foods, err = fetchNutritionixNatural(ctx, key)
+		}
+		if err != nil || len(foods) == 0 {
+			return nil, &statusError{http.StatusNotFound, "No food found for " + id}
+		}
+		p = foods[0].product()
+	case "label":
+		foods, err := searchUSDA(ctx, strings.ReplaceAll(key, "_", " "), []string{"Survey (FNDDS)"}, 1, 1)
+		if err != nil || len(foods) == 0 {
+			return nil, &statusError{http.StatusNotFound, "No food found for " + id}
+		}
+		p = foods[0].product()
+	case "barcode":
+		return lookupBarcode(ctx, key)
+	default:
+		if _, err := strconv.ParseUint(id, 10, 64); err == nil {
+			return lookupBarcode(ctx, id)
+		}
+		return nil, &statusError{http.StatusBadRequest, fmt.Sprintf("Unknown food id %q", id)}
+	}
+
+	rememberProduct(p, 1)
+	productCache.Set(id, p)
+	return p, nil
+}
@@ -0,0 +1,283 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+)
+
+const maxRecipeIngredients = 50
+
+// recipeIngredient is one line of a recipe. The food is an FDC ID, a
+// barcode or free text; Text alone may carry its quantity, as in "2 cups
+// flour, sifted". Grams wins over Quantity, a measure such as "1/2 cup".
+type recipeIngredient struct {
+	Text     string  `json:"text"`
+	FdcID    int     `json:"fdcId"`
+	Barcode  string  `json:"barcode"`
+	Quantity string  `json:"quantity"`
+	Grams    float64 `json:"grams"`
+}
+
+// RecipeAnalyzeHandler totals the nutrition of a recipe and divides it into
+// servings. Ingredients are resolved through the USDA lookups behind
+// FoodScanHandler and ParseMealHandler and the barcode lookup chain. The
+// optional yield factor, cooked weight over raw weight, or the cooked weight
+// itself only changes the per-100 g values of the dish, since cooking
+// loses or gains water rather than nutrients.
+func RecipeAnalyzeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appkey := r.Header.Get("X-APP-KEY")
+	if appkey != config.Global.SUSHI_SECRET_KEY {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := nutritionOptionsFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req struct {
+		Name         string             `json:"name"`
+		Servings     float64            `json:"servings"`
+		Ingredients  []recipeIngredient `json:"ingredients"`
+		YieldFactor  float64            `json:"yieldFactor"`
+		CookedWeight float64            `json:"cookedWeight"` // grams, wins over yieldFactor
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Ingredients) == 0 {
+		http.Error(w, "No recipe ingredients provided", http.StatusBadRequest)
+		return
+	}
+	if len(req.Ingredients) > maxRecipeIngredients {
+		http.Error(w, fmt.Sprintf("At most %d ingredients are supported", maxRecipeIngredients), http.StatusBadRequest)
+		return
+	}
+	if req.Servings == 0 {
+		req.Servings = 1
+	}
+	if req.Servings < 0 || req.YieldFactor < 0 || req.CookedWeight < 0 {
+		http.Error(w, "servings, yieldFactor and cookedWeight must be positive", http.StatusBadRequest)
+		return
+	}
+
+	items, unresolved, totals, rawGrams := resolveRecipeIngredients(r.Context(), req.Ingredients)
+	if len(items) == 0 {
+		http.Error(w, "No ingredients could be resolved", http.StatusNotFound)
+		return
+	}
+
+	cookedGrams := rawGrams
+	switch {
+	case req.CookedWeight > 0:
+		cookedGrams = req.CookedWeight
+	case req.YieldFactor > 0:
+		cookedGrams = rawGrams * req.YieldFactor
+	}
+	total := common.NormalizeNutrients(common.SumNutrients(totals...))
+	data := map[string]interface{}{
+		"name":          req.Name,
+		"servings":      req.Servings,
+		"items":         items,
+		"unresolved":    unresolved,
+		"complete":      len(unresolved) == 0,
+		"rawWeight":     common.RoundTo(rawGrams, 1),
+		"cookedWeight":  common.RoundTo(cookedGrams, 1),
+		"yieldFactor":   nil,
+		"servingWeight": common.RoundTo(cookedGrams/req.Servings, 1),
+		"total": map[string]interface{}{
+			"nutrition": common.ChunkArray(total, 6),
+		},
+		"perServing": common.ChunkArray(common.ScaleNutrition(total, 1/req.Servings, common.PerServing), 6),
+		"per100g":    nil,
+	}
+	if rawGrams > 0 {
+		data["yieldFactor"] = common.RoundTo(cookedGrams/rawGrams, 3)
+	}
+	if cookedGrams > 0 {
+		data["per100g"] = common.ChunkArray(common.ScaleNutrition(total, 100/cookedGrams, common.Per100g), 6)
+	}
+	resp := map[string]interface{}{
+		"message": "Recipe analyzed successfully",
+		"data":    data,
+	}
+	annotateNutrition(resp, opts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// resolveRecipeIngredients looks up every line concurrently, keeping the
+// recipe order. It returns the resolved and unresolved lines, the
+// nutrients of each resolved line and their total weight in grams.
+func resolveRecipeIngredients(ctx context.Context, lines []recipeIngredient) ([]map[string]interface{}, []map[string]interface{}, [][]common.Nutrient, float64) {
+	entries := make([]map[string]interface{}, len(lines))
+	nutrients := make([][]common.Nutrient, len(lines))
+	errs := make([]error, len(lines))
+
+	var wg sync.WaitGroup
+	for i, line := range lines {
+		wg.Add(1)
+		go func(i int, line recipeIngredient) {
+			defer wg.Done()
+			entries[i], nutrients[i], errs[i] = resolveRecipeIngredient(ctx, line)
+		}(i, line)
+	}
+	wg.Wait()
+
+	items, unresolved := []map[string]interface{}{}, []map[string]interface{}{}
+	var totals [][]common.Nutrient
+	rawGrams := 0.0
+	for i, line := range lines {
+		if errs[i] != nil {
+			entry := map[string]interface{}{"index": i, "text": line.Text, "error": errs[i].Error()}
+			if line.FdcID > 0 {
+				entry["fdcId"] = line.FdcID
+			}
+			if line.Barcode != "" {
+				entry["barcode"] = line.Barcode
+			}
+			unresolved = append(unresolved, entry)
+			continue
+		}
+		entries[i]["index"] = i
+		items = append(items, entries[i])
+		totals = append(totals, nutrients[i])
+		grams, _ := entries[i]["grams"].(float64)
+		rawGrams += grams
+	}
+	return items, unresolved, totals, rawGrams
+}
+
+// resolveRecipeIngredient finds the food of one line and scales its
+// nutrients to the line's quantity, the food's default portion when none is
+// given. Volumes convert through a portion given in a volume, or at the
+// density of water; see common.ResolvePortion.
+func resolveRecipeIngredient(ctx context.Context, line recipeIngredient) (map[string]interface{}, []common.Nutrient, error) {
+	var portionIn *common.PortionInput
+	if line.Grams > 0 || strings.TrimSpace(line.Quantity) != "" {
+		portionIn = &common.PortionInput{Grams: line.Grams, Measure: line.Quantity}
+	}
+
+	switch {
+	case line.FdcID > 0:
+		food, portions, err := fetchUSDAFood(ctx, line.FdcID)
+		if err != nil {
+			fmt.Printf("RecipeAnalyzeHandler: USDA error: %v\n", err)
+			return nil, nil, fmt.Errorf("no food found for FDC ID %d", line.FdcID)
+		}
+		p := food.product()
+		rememberProduct(p, 1)
+		if len(portions) == 0 {
+			// Branded foods have no FNDDS portions, only a serving
+			portions = servingPortions(p)
+		}
+		portion, err := common.ResolvePortion(portions, portionIn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return recipeEntry(line, p, portion), common.ScaleNutrients(food.FoodNutrients, portion.GramWeight), nil
+
+	case line.Barcode != "":
+		p, err := lookupBarcode(ctx, line.Barcode)
+		if err != nil {
+			return nil, nil, err
+		}
+		per100g, _ := p.DualNutrition()
+		if per100g == nil {
+			return nil, nil, fmt.Errorf("no per-100 g nutrition for barcode %s", line.Barcode)
+		}
+		portion, err := common.ResolvePortion(servingPortions(p), portionIn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return recipeEntry(line, p, portion), common.ScaleNutrients(nutrientsOf(per100g), portion.GramWeight), nil
+
+	case strings.TrimSpace(line.Text) != "":
+		var (
+			res map[string]interface{}
+			n   []common.Nutrient
+			err error
+		)
+		if portionIn != nil {
+			res, n, err = lookupScannedFood(ctx, line.Text, portionIn, noStages)
+		} else {
+			item, ok := common.ParseIngredient(line.Text)
+			if !ok {
+				return nil, nil, fmt.Errorf("no food found in %q", line.Text)
+			}
+			res, n, err = lookupMealItem(ctx, item)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if res["nutrition"] == nil {
+			return nil, nil, fmt.Errorf("no nutrition data found")
+		}
+		entry := map[string]interface{}{
+			"text":      line.Text,
+			"food":      res["foodName"],
+			"id":        fmt.Sprintf("usda:%d", res["fdcId"]),
+			"source":    "usda",
+			"grams":     res["servingWeight"],
+			"serving":   res["servingSize"],
+			"nutrition": res["nutrition"],
+		}
+		if note, ok := res["note"]; ok {
+			entry["note"] = note
+		}
+		return entry, n, nil
+	}
+	return nil, nil, fmt.Errorf("ingredient needs a text, fdcId or barcode")
+}
+
+// servingPortions offers a product's serving as its only portion, so a
+// count such as "2" or a volume such as "250 ml" resolves against it.
+func servingPortions(p *common.Product) []common.FoodPortion {
+	grams := p.ServingWeight()
+	if grams <= 0 {
+		return nil
+	}
+	return []common.FoodPortion{{Index: 0, Description: p.ServingSize, GramWeight: grams}}
+}
+
+// recipeEntry describes a line resolved to a looked-up product.
+func recipeEntry(line recipeIngredient, p *common.Product, portion common.FoodPortion) map[string]interface{} {
+	per100g, _ := p.DualNutrition()
+	return map[string]interface{}{
+		"text":      line.Text,
+		"food":      p.Name,
+		"id":        p.ID,
+		"source":    p.Source,
+		"grams":     common.RoundTo(portion.GramWeight, 1),
+		"serving":   portion.Label(),
+		"nutrition": common.ChunkArray(common.ScaleNutrition(per100g, portion.GramWeight/100, common.PerServing), 6),
+	}
+}
+
+// nutrientsOf turns a normalized nutrition list back into provider
+// nutrients, skipping unknown amounts.
+func nutrientsOf(list []map[string]interface{}) []common.Nutrient {
+	var out []common.Nutrient
+	for _, n := range list {
+		amount, ok := n["amount"].(float64)
+		if !ok {
+			continue
+		}
+		id, _ := n["id"].(string)
+		name, _ := n["name"].(string)
+		unit, _ := n["unit"].(string)
+		out = append(out, common.Nutrient{ID: id, NutrientName: name, Value: amount, UnitName: unit})
+	}
+	return out
+}
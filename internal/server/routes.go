@@ -1,13 +1,71 @@
 package server
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Sush1sui/internal/additives"
+	"github.com/Sush1sui/internal/alternatives"
+	"github.com/Sush1sui/internal/compare"
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/dailyvalue"
+	"github.com/Sush1sui/internal/frontofpack"
+	"github.com/Sush1sui/internal/glycemic"
+	"github.com/Sush1sui/internal/nutrients"
+)
 
 func NewRouter() http.Handler {
+	initProductStore(config.Global.CACHE_TTL, config.Global.FOOD_LIST_PATH)
+	if config.Global.DV_TABLES_PATH != "" {
+		if err := dailyvalue.LoadFile(config.Global.DV_TABLES_PATH); err != nil {
+			fmt.Println("Error loading daily value tables:", err)
+		}
+	}
+	if config.Global.NUTRIENT_PROFILES_PATH != "" {
+		if err := nutrients.LoadProfiles(config.Global.NUTRIENT_PROFILES_PATH); err != nil {
+			fmt.Println("Error loading nutrient profiles:", err)
+		}
+	}
+	if config.Global.LABEL_RULES_PATH != "" {
+		if err := frontofpack.LoadFile(config.Global.LABEL_RULES_PATH); err != nil {
+			fmt.Println("Error loading label rules:", err)
+		}
+	}
+	if config.Global.ADDITIVE_CONCERNS_PATH != "" {
+		if err := additives.LoadFile(config.Global.ADDITIVE_CONCERNS_PATH); err != nil {
+			fmt.Println("Error loading additive concerns:", err)
+		}
+	}
+	if config.Global.GI_TABLE_PATH != "" {
+		if err := glycemic.LoadFile(config.Global.GI_TABLE_PATH); err != nil {
+			fmt.Println("Error loading GI table:", err)
+		}
+	}
+	if config.Global.COMPARE_RULES_PATH != "" {
+		if err := compare.LoadFile(config.Global.COMPARE_RULES_PATH); err != nil {
+			fmt.Println("Error loading comparison rules:", err)
+		}
+	}
+	if config.Global.ALTERNATIVE_SCORE_PATH != "" {
+		if err := alternatives.LoadFile(config.Global.ALTERNATIVE_SCORE_PATH); err != nil {
+			fmt.Println("Error loading alternative score weights:", err)
+		}
+	}
+
 	mux := http.NewServeMux()
 	
 	mux.HandleFunc("/", IndexHandler)
 	mux.HandleFunc("/barcode", BarcodeHandler)
 	mux.HandleFunc("/food-scan", FoodScanHandler)
+	mux.HandleFunc("/barcode/stream", BarcodeStreamHandler)
+	mux.HandleFunc("/food-scan/stream", FoodScanStreamHandler)
+	mux.HandleFunc("/v1/search", SearchHandler)
+	mux.HandleFunc("/v1/autocomplete", AutocompleteHandler)
+	mux.HandleFunc("/v1/foods/", FoodHandler)
+	mux.HandleFunc("/v1/parse-meal", ParseMealHandler)
+	mux.HandleFunc("/v1/compare", CompareHandler)
+	mux.HandleFunc("/v1/recipes/analyze", RecipeAnalyzeHandler)
+	mux.HandleFunc("/v1/barcodes/batch", BarcodesBatchHandler)
 	
 	return mux
-}
\ No newline at end of file
+}
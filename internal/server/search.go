@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+)
+
+// usdaDataTypes maps the dataType filter values clients send to USDA's names.
+var usdaDataTypes = map[string]string{
+	"foundation": "Foundation",
+	"sr legacy":  "SR Legacy",
+	"fndds":      "Survey (FNDDS)",
+	"branded":    "Branded",
+}
+
+const maxSearchPageSize = 50
+
+// SearchHandler looks up foods by name across USDA, Nutritionix and Open Food
+// Facts. Results are deduplicated, with USDA preferred over Nutritionix and
+// Nutritionix over Open Food Facts, matching the barcode lookup chain. A
+// dataType filter searches USDA alone.
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appkey := r.Header.Get("X-APP-KEY")
+	if appkey != config.Global.SUSHI_SECRET_KEY {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := nutritionOptionsFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		http.Error(w, "No search query provided", http.StatusBadRequest)
+		return
+	}
+	page, pageSize, err := parsePaging(q.Get("page"), q.Get("pageSize"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var dataTypes []string
+	for _, dt := range splitList(q.Get("dataType")) {
+		name, ok := usdaDataTypes[dt]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Unknown dataType %q", dt), http.StatusBadRequest)
+			return
+		}
+		dataTypes = append(dataTypes, name)
+	}
+
+	order := []string{"usda", "nutritionix", "openfoodfacts"}
+	sources := map[string]bool{"usda": true, "nutritionix": true, "openfoodfacts": true}
+	if list := splitList(q.Get("sources")); len(list) > 0 {
+		requested := map[string]bool{}
+		for _, s := range list {
+			if !sources[s] {
+				http.Error(w, fmt.Sprintf("Unknown source %q (available: %s)", s, strings.Join(order, ", ")), http.StatusBadRequest)
+				return
+			}
+			requested[s] = true
+		}
+		sources = requested
+	}
+	if len(dataTypes) > 0 {
+		// the other providers have no data types to filter by
+		if !sources["usda"] {
+			http.Error(w, "dataType only applies to the usda source", http.StatusBadRequest)
+			return
+		}
+		sources = map[string]bool{"usda": true}
+	}
+
+	found := make([][]*common.Product, len(order))
+	errs := make([]error, len(order))
+	var wg sync.WaitGroup
+	for i, source := range order {
+		if !sources[source] {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+			found[i], errs[i] = searchSource(r.Context(), source, query, dataTypes, page, pageSize)
+		}(i, source)
+	}
+	wg.Wait()
+
+	results := []map[string]interface{}{}
+	failures := map[string]string{}
+	seen := map[string]bool{}
+	for i, products := range found {
+		if errs[i] != nil {
+			fmt.Printf("SearchHandler: %s search error: %v\n", order[i], errs[i])
+			failures[order[i]] = errs[i].Error()
+		}
+		for _, p := range products {
+			key := p.DedupKey()
+			if p.Name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			rememberProduct(p, 0)
+			results = append(results, searchResult(p))
+		}
+	}
+	if len(results) == 0 && len(failures) > 0 {
+		http.Error(w, "Failed to fetch data.", http.StatusBadGateway)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"message": "Search results retrieved successfully",
+		"data": map[string]interface{}{
+			"query":    query,
+			"page":     page,
+			"pageSize": pageSize,
+			"results":  results,
+			"errors":   failures,
+		},
+	}
+	annotateNutrition(resp, opts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// searchSource runs one provider's search and normalizes its results.
+func searchSource(ctx context.Context, source, query string, dataTypes []string, page, pageSize int) ([]*common.Product, error) {
+	var products []*common.Product
+	switch source {
+	case "usda":
+		foods, err := searchUSDA(ctx, query, dataTypes, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range foods {
+			products = append(products, f.product())
+		}
+	case "nutritionix":
+		// instant search has no paging, so page through its results here
+		foods, err := searchNutritionix(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		start, end := (page-1)*pageSize, page*pageSize
+		for i := start; i < end && i < len(foods); i++ {
+			products = append(products, foods[i].product())
+		}
+	case "openfoodfacts":
+		items, err := searchOFF(ctx, query, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range items {
+			products = append(products, p.product())
+		}
+	}
+	return products, nil
+}
+
+// searchResult renders a product with the fields needed to look it up again.
+func searchResult(p *common.Product) map[string]interface{} {
+	data := p.Data()
+	data["id"] = p.ID
+	data["source"] = p.Source
+	if p.DataType != "" {
+		data["dataType"] = p.DataType
+	}
+	if p.Barcode != "" {
+		data["barcode"] = p.Barcode
+	}
+	return data
+}
+
+// parsePaging reads the page and pageSize query parameters, defaulting to the
+// first page of 10 results.
+func parsePaging(pageParam, sizeParam string) (int, int, error) {
+	page, pageSize := 1, 10
+	if pageParam != "" {
+		v, err := strconv.Atoi(pageParam)
+		if err != nil || v < 1 {
+			return 0, 0, fmt.Errorf("Invalid page %q", pageParam)
+		}
+		page = v
+	}
+	if sizeParam != "" {
+		v, err := strconv.Atoi(sizeParam)
+		if err != nil || v < 1 || v > maxSearchPageSize {
+			return 0, 0, fmt.Errorf("Invalid pageSize %q (1-%d)", sizeParam, maxSearchPageSize)
+		}
+		pageSize = v
+	}
+	return page, pageSize, nil
+}
+
+// splitList splits a comma-separated query parameter into lowercase values.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval keeps proxies from closing a stream while a stage is
+// slow, such as a Hugging Face cold start.
+var heartbeatInterval = 10 * time.Second
+
+// Keys of the partial results sent with the "nutrition" event.
+var nutritionFields = []string{
+	"foodName", "name", "brand", "fdcId", "nutrition", "per100g", "perServing",
+	"serving", "servingSize", "servingWeight", "portion", "portions",
+}
+
+// eventStream writes Server-Sent Events. Every event's data is one JSON
+// object; failures end the stream with an "error" event. Once the client
+// goes away or a write fails nothing more is written.
+type eventStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	stop    chan struct{}
+	// done is closed when the heartbeat has stopped
+	done   chan struct{}
+	closed bool
+}
+
+// startEventStream sends the event stream headers and starts the
+// heartbeat, which runs until the stream is closed or ctx ends. It
+// reports false when the connection cannot stream.
+func startEventStream(ctx context.Context, w http.ResponseWriter) (*eventStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	s := &eventStream{w: w, flusher: flusher, stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if !s.write(": heartbeat\n\n") {
+					return
+				}
+			case <-ctx.Done():
+				s.mu.Lock()
+				s.closed = true
+				s.mu.Unlock()
+				return
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	return s, true
+}
+
+// write sends one frame and reports whether the stream is still open. A
+// failed write closes it, the client is gone.
+func (s *eventStream) write(frame string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if _, err := fmt.Fprint(s.w, frame); err != nil {
+		fmt.Printf("eventStream: write failed: %v\n", err)
+		s.closed = true
+		return false
+	}
+	s.flusher.Flush()
+	return true
+}
+
+// send emits one event.
+func (s *eventStream) send(event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		fmt.Printf("eventStream: encoding %s event: %v\n", event, err)
+		return
+	}
+	s.write(fmt.Sprintf("event: %s\ndata: %s\n\n", event, payload))
+}
+
+// fail emits an "error" event with the status err carries and closes the
+// stream.
+func (s *eventStream) fail(err error) {
+	status := http.StatusInternalServerError
+	if se, ok := err.(*statusError); ok {
+		status = se.status
+	}
+	s.send("error", map[string]interface{}{"status": status, "message": err.Error()})
+	s.close()
+}
+
+// finish emits the final "result" event, the response the non-streaming
+// endpoint would send, and closes the stream.
+func (s *eventStream) finish(resp map[string]interface{}) {
+	s.send("result", resp)
+	s.close()
+}
+
+// close stops the heartbeat and waits for it; nothing is written
+// afterwards, since the handler is about to return.
+func (s *eventStream) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	close(s.stop)
+	<-s.done
+}
+
+// pick copies the given fields of data that are present.
+func pick(data map[string]interface{}, keys []string) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, k := range keys {
+		if v, ok := data[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// stages reports the stages of a pipeline as events. The partial
+// nutrition is annotated the way the final response is.
+func (s *eventStream) stages(opts nutritionOptions) stage {
+	return func(event string, data interface{}) {
+		if event == "nutrition" {
+			annotateLists(data, opts)
+		}
+		s.send(event, data)
+	}
+}
+
+// streamPipeline runs a pipeline, sending its stages as events and its
+// response as the "result" event. The pipeline gets the request's context,
+// which ends when the client goes away.
+func streamPipeline(w http.ResponseWriter, r *http.Request, opts nutritionOptions, run func(ctx context.Context, report stage) (map[string]interface{}, error)) {
+	stream, ok := startEventStream(r.Context(), w)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	resp, err := run(r.Context(), stream.stages(opts))
+	if err != nil {
+		stream.fail(err)
+		return
+	}
+	stream.finish(resp)
+}
+
+// FoodScanStreamHandler is FoodScanHandler reporting each stage as a
+// Server-Sent Event: "received" once the image is decoded, "classified"
+// with the label and score, "nutrition" as soon as the FNDDS food is
+// resolved, "ingredients", then "result" with the full response. Plate
+// mode is not streamed.
+func FoodScanStreamHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := readFoodScanRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.Plate {
+		http.Error(w, "Plate mode is not available as a stream, use /food-scan", http.StatusBadRequest)
+		return
+	}
+	streamPipeline(w, r, req.Opts, func(ctx context.Context, report stage) (map[string]interface{}, error) {
+		return foodScanResponse(ctx, req, report)
+	})
+}
+
+// BarcodeStreamHandler is BarcodeHandler reporting each stage as a
+// Server-Sent Event: "received", "found" with the product's source and
+// name once the lookup chain answers, "nutrition", "ingredients", then
+// "result" with the full response.
+func BarcodeStreamHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := readBarcodeRequest(w, r)
+	if !ok {
+		return
+	}
+	streamPipeline(w, r, req.Opts, func(ctx context.Context, report stage) (map[string]interface{}, error) {
+		return barcodeResponse(ctx, req, report)
+	})
+}
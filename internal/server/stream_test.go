@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sseEvent struct {
+	event string
+	data  map[string]interface{}
+}
+
+// parseEvents splits a recorded stream into its events, skipping
+// heartbeat comments.
+func parseEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	if !strings.HasSuffix(body, "\n\n") {
+		t.Errorf("stream %q does not end with a blank line", body)
+	}
+	var events []sseEvent
+	for _, frame := range strings.Split(strings.TrimSuffix(body, "\n\n"), "\n\n") {
+		if strings.HasPrefix(frame, ":") {
+			continue
+		}
+		lines := strings.Split(frame, "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "event: ") || !strings.HasPrefix(lines[1], "data: ") {
+			t.Fatalf("malformed frame %q", frame)
+		}
+		ev := sseEvent{event: strings.TrimPrefix(lines[0], "event: ")}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &ev.data); err != nil {
+			t.Fatalf("frame %q: %v", frame, err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func eventNames(events []sseEvent) string {
+	var names []string
+	for _, ev := range events {
+		names = append(names, ev.event)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestStreamPipelineEvents(t *testing.T) {
+	opts := batchOptions(t)
+	w := httptest.NewRecorder()
+	streamPipeline(w, httptest.NewRequest(http.MethodPost, "/food-scan/stream", nil), opts, func(ctx context.Context, report stage) (map[string]interface{}, error) {
+		report("received", map[string]interface{}{"bytes": 3})
+		report("nutrition", map[string]interface{}{"foodName": "apple"})
+		report("ingredients", map[string]interface{}{"ingredients": nil})
+		return map[string]interface{}{"message": "ok"}, nil
+	})
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type %q", ct)
+	}
+	events := parseEvents(t, w.Body.String())
+	if got := eventNames(events); got != "received,nutrition,ingredients,result" {
+		t.Errorf("events %s", got)
+	}
+	if last := events[len(events)-1]; last.data["message"] != "ok" {
+		t.Errorf("result %v", last.data)
+	}
+}
+
+func TestStreamPipelineError(t *testing.T) {
+	opts := batchOptions(t)
+	w := httptest.NewRecorder()
+	streamPipeline(w, httptest.NewRequest(http.MethodPost, "/food-scan/stream", nil), opts, func(ctx context.Context, report stage) (map[string]interface{}, error) {
+		report("received", map[string]interface{}{"bytes": 3})
+		return nil, &statusError{http.StatusUnprocessableEntity, "No portion data for this food"}
+	})
+
+	events := parseEvents(t, w.Body.String())
+	if got := eventNames(events); got != "received,error" {
+		t.Fatalf("events %s", got)
+	}
+	if data := events[1].data; data["status"] != 422.0 || data["message"] != "No portion data for this food" {
+		t.Errorf("error event %v", data)
+	}
+}
+
+func TestStreamPipelineHeartbeat(t *testing.T) {
+	defer func(d time.Duration) { heartbeatInterval = d }(heartbeatInterval)
+	heartbeatInterval = time.Millisecond
+
+	opts := batchOptions(t)
+	w := httptest.NewRecorder()
+	streamPipeline(w, httptest.NewRequest(http.MethodPost, "/food-scan/stream", nil), opts, func(ctx context.Context, report stage) (map[string]interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return map[string]interface{}{}, nil
+	})
+	body := w.Body.String()
+	if !strings.Contains(body, ": heartbeat\n\n") {
+		t.Errorf("no heartbeat during a slow stage: %q", body)
+	}
+
+	// the heartbeat has stopped once the handler returns
+	time.Sleep(10 * time.Millisecond)
+	if w.Body.String() != body {
+		t.Errorf("written after the stream closed: %q", strings.TrimPrefix(w.Body.String(), body))
+	}
+}
+
+func TestStreamPipelineClientGone(t *testing.T) {
+	defer func(d time.Duration) { heartbeatInterval = d }(heartbeatInterval)
+	heartbeatInterval = time.Millisecond
+
+	opts := batchOptions(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodPost, "/food-scan/stream", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	var before string
+	streamPipeline(w, r, opts, func(ctx context.Context, report stage) (map[string]interface{}, error) {
+		report("received", map[string]interface{}{"bytes": 3})
+		cancel()
+		<-ctx.Done()
+		// let the heartbeat see the cancel
+		time.Sleep(10 * time.Millisecond)
+		before = w.Body.String()
+		report("nutrition", map[string]interface{}{})
+		time.Sleep(10 * time.Millisecond)
+		return nil, ctx.Err()
+	})
+
+	if w.Body.String() != before {
+		t.Errorf("written after the client went away: %q", strings.TrimPrefix(w.Body.String(), before))
+	}
+	if got := eventNames(parseEvents(t, before)); got != "received" {
+		t.Errorf("events %s", got)
+	}
+}
+
+// failingWriter is a streaming response whose writes fail once the client
+// has gone.
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	gone   bool
+	writes int
+}
+
+func (f *failingWriter) Write(b []byte) (int, error) {
+	if f.gone {
+		f.writes++
+		return 0, errors.New("broken pipe")
+	}
+	return f.ResponseRecorder.Write(b)
+}
+
+func TestStreamPipelineWriteError(t *testing.T) {
+	opts := batchOptions(t)
+	w := &failingWriter{ResponseRecorder: httptest.NewRecorder()}
+	streamPipeline(w, httptest.NewRequest(http.MethodPost, "/food-scan/stream", nil), opts, func(ctx context.Context, report stage) (map[string]interface{}, error) {
+		report("received", map[string]interface{}{"bytes": 3})
+		w.gone = true
+		report("nutrition", map[string]interface{}{})
+		report("ingredients", map[string]interface{}{})
+		return map[string]interface{}{}, nil
+	})
+
+	if w.writes != 1 {
+		t.Errorf("%d writes after the first failure, want 1", w.writes)
+	}
+	if got := eventNames(parseEvents(t, w.Body.String())); got != "received" {
+		t.Errorf("events %s", got)
+	}
+}
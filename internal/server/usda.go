@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Sush1sui/internal/common"
+	"github.com/Sush1sui/internal/config"
+	"github.com/Sush1sui/internal/units"
+)
+
+// fetchFoodPortions loads the FNDDS household portions of a USDA food,
+// ordered by sequence number.
+func fetchFoodPortions(ctx context.Context, fdcID int) ([]common.FoodPortion, error) {
+	_, portions, err := fetchUSDAFood(ctx, fdcID)
+	return portions, err
+}
+
+// fetchUSDAFood loads a single USDA food by FDC ID together with its
+// household portions.
+func fetchUSDAFood(ctx context.Context, fdcID int) (usdaFood, []common.FoodPortion, error) {
+	usdaURL := fmt.Sprintf("https://api.nal.usda.gov/fdc/v1/food/%d?api_key=%s", fdcID, config.Global.USDA_API_KEY)
+	usdaReq, _ := http.NewRequestWithContext(ctx, "GET", usdaURL, nil)
+	resp, err := http.DefaultClient.Do(usdaReq)
+	if err != nil {
+		return usdaFood{}, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return usdaFood{}, nil, fmt.Errorf("USDA food %d returned status %d", fdcID, resp.StatusCode)
+	}
+
+	// the details endpoint nests nutrient names, unlike foods/search
+	var food struct {
+		usdaFood
+		FoodNutrients []struct {
+			Nutrient struct {
+				Number   string `json:"number"`
+				Name     string `json:"name"`
+				UnitName string `json:"unitName"`
+			} `json:"nutrient"`
+			Amount float64 `json:"amount"`
+		} `json:"foodNutrients"`
+		FoodPortions []struct {
+			PortionDescription string  `json:"portionDescription"`
+			GramWeight         float64 `json:"gramWeight"`
+			SequenceNumber     int     `json:"sequenceNumber"`
+		} `json:"foodPortions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&food); err != nil {
+		return usdaFood{}, nil, err
+	}
+
+	f := food.usdaFood
+	f.FoodNutrients = nil
+	for _, n := range food.FoodNutrients {
+		f.FoodNutrients = append(f.FoodNutrients, common.Nutrient{
+			Number:       n.Nutrient.Number,
+			NutrientName: n.Nutrient.Name,
+			Value:        n.Amount,
+			UnitName:     n.Nutrient.UnitName,
+		})
+	}
+
+	portions := make([]common.FoodPortion, 0, len(food.FoodPortions))
+	sequence := make([]int, 0, len(food.FoodPortions))
+	for _, p := range food.FoodPortions {
+		portions = append(portions, common.FoodPortion{
+			Description: p.PortionDescription,
+			GramWeight:  p.GramWeight,
+		})
+		sequence = append(sequence, p.SequenceNumber)
+	}
+	return f, common.SortPortions(portions, sequence), nil
+}
+
+// usdaFood is a food as returned by the USDA foods/search endpoint.
+type usdaFood struct {
+	FdcID           int               `json:"fdcId"`
+	DataType        string            `json:"dataType"`
+	Description     string            `json:"description"`
+	BrandOwner      string            `json:"brandOwner"`
+	GtinUpc         string            `json:"gtinUpc"`
+	Ingredients     string            `json:"ingredients"`
+	ServingSize     float64           `json:"servingSize"`
+	ServingSizeUnit string            `json:"servingSizeUnit"`
+	PackageWeight   string            `json:"packageWeight"`
+	FoodNutrients   []common.Nutrient `json:"foodNutrients"`
+	// foodCategory is a string in search results and an object with a
+	// description in food details
+	FoodCategory        interface{} `json:"foodCategory"`
+	BrandedFoodCategory string      `json:"brandedFoodCategory"`
+}
+
+func (f usdaFood) product() *common.Product {
+	servingSize := "N/A"
+	if f.ServingSize > 0 {
+		servingSize = fmt.Sprintf("%v%v", f.ServingSize, f.ServingSizeUnit)
+	}
+	// search and detail values are per 100 g for every USDA data type
+	servingGrams, _ := units.ServingGrams(f.ServingSize, f.ServingSizeUnit)
+	return &common.Product{
+		ID:           fmt.Sprintf("usda:%d", f.FdcID),
+		Source:       "usda",
+		DataType:     f.DataType,
+		Barcode:      f.GtinUpc,
+		Name:         f.Description,
+		Brand:        f.BrandOwner,
+		Ingredients:  f.Ingredients,
+		ServingSize:  servingSize,
+		ServingGrams: servingGrams,
+		Basis:        common.Per100g,
+		Nutrition:    common.NormalizeNutrients(f.FoodNutrients),
+		Category:     f.category(),
+	}
+}
+
+// category reads foodCategory in either form, falling back to
+// brandedFoodCategory.
+func (f usdaFood) category() string {
+	switch v := f.FoodCategory.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if description, ok := v["description"].(string); ok {
+			return description
+		}
+	}
+	return f.BrandedFoodCategory
+}
+
+// searchUSDA runs a USDA foods/search query. A pageSize of 0 leaves paging
+// to the API defaults.
+func searchUSDA(ctx context.Context, query string, dataTypes []string, page, pageSize int) ([]usdaFood, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("api_key", config.Global.USDA_API_KEY)
+	for _, dt := range dataTypes {
+		params.Add("dataType", dt)
+	}
+	if pageSize > 0 {
+		params.Set("pageSize", strconv.Itoa(pageSize))
+		params.Set("pageNumber", strconv.Itoa(page))
+	}
+
+	usdaReq, _ := http.NewRequestWithContext(ctx, "GET", "https://api.nal.usda.gov/fdc/v1/foods/search?"+params.Encode(), nil)
+	resp, err := http.DefaultClient.Do(usdaReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("USDA search returned status %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Foods []usdaFood `json:"foods"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data.Foods, nil
+}
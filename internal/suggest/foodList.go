@@ -0,0 +1,71 @@
+package suggest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadFoodList reads an imported food list CSV with a header row. USDA
+// FoodData Central food.csv works as is (fdc_id, description); other lists
+// need an id and a name column, and may have a brand column. Their ids are
+// used as is, so they should be lookup ids such as "usda:123" or barcodes.
+func LoadFoodList(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading food list header: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	usda := true
+	idCol, ok := col["fdc_id"]
+	if !ok {
+		usda = false
+		if idCol, ok = col["id"]; !ok {
+			return nil, fmt.Errorf("food list %s has no fdc_id or id column", path)
+		}
+	}
+	nameCol, ok := col["description"]
+	if !ok {
+		if nameCol, ok = col["name"]; !ok {
+			return nil, fmt.Errorf("food list %s has no description or name column", path)
+		}
+	}
+	brandCol, hasBrand := col["brand"]
+
+	var entries []Entry
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return entries, err
+		}
+		if idCol >= len(rec) || nameCol >= len(rec) {
+			continue
+		}
+		e := Entry{ID: rec[idCol], Name: rec[nameCol], Source: "foodlist"}
+		if usda {
+			e.ID, e.Source = "usda:"+rec[idCol], "usda"
+		}
+		if hasBrand && brandCol < len(rec) {
+			e.Brand = rec[brandCol]
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,38 @@
+package suggest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeList(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "foods.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFoodList(t *testing.T) {
+	entries, err := LoadFoodList(writeList(t, "fdc_id,data_type,description\n123,foundation_food,\"Apples, raw\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0] != (Entry{ID: "usda:123", Name: "Apples, raw", Source: "usda"}) {
+		t.Errorf("USDA list = %+v", entries)
+	}
+
+	entries, err = LoadFoodList(writeList(t, "ID,Name,Brand\n0123,Granola,Acme\nshort\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0] != (Entry{ID: "0123", Name: "Granola", Brand: "Acme", Source: "foodlist"}) {
+		t.Errorf("custom list = %+v", entries)
+	}
+
+	if _, err := LoadFoodList(writeList(t, "code,title\n1,x\n")); err == nil {
+		t.Error("list without id and name columns loaded")
+	}
+}
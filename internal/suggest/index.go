@@ -0,0 +1,332 @@
+package suggest
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// maxCandidates bounds how many entries one query scores, keeping very short
+// queries fast on large food lists.
+const maxCandidates = 5000
+
+// sweepEvery is how many new entries pass between sweeps of expired ones.
+const sweepEvery = 1000
+
+// Entry is a suggestable food. ID is what clients pass to a follow-up lookup.
+type Entry struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Brand  string `json:"brand,omitempty"`
+	Source string `json:"source"`
+	// Weight boosts entries users actually look up.
+	Weight float64 `json:"-"`
+	// Expires drops the entry once passed; the zero time never expires.
+	Expires time.Time `json:"-"`
+}
+
+func (e Entry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && now.After(e.Expires)
+}
+
+// Suggestion is an entry with the score it was ranked by.
+type Suggestion struct {
+	Entry
+	Score float64 `json:"score"`
+}
+
+// Index is an in-memory prefix and trigram index over food names. Prefix
+// matches are found by binary search over the sorted word list; trigrams
+// catch typos when prefixes find too little.
+type Index struct {
+	mu       sync.RWMutex
+	entries  []Entry
+	byID     map[string]int
+	names    map[string][]int // normalized name -> entry positions
+	words    []string         // sorted, unique
+	pending  []string         // new words not yet merged into words
+	wordRefs map[string][]int // word -> entry positions
+	trigrams map[string][]int // trigram -> entry positions
+	added    int              // entries added since the last sweep
+}
+
+func NewIndex() *Index {
+	ix := &Index{}
+	ix.reset()
+	return ix
+}
+
+func (ix *Index) reset() {
+	ix.entries, ix.words, ix.pending = nil, nil, nil
+	ix.byID = map[string]int{}
+	ix.names = map[string][]int{}
+	ix.wordRefs = map[string][]int{}
+	ix.trigrams = map[string][]int{}
+}
+
+// Add inserts an entry, or bumps the weight of one already indexed and
+// extends its expiry.
+func (ix *Index) Add(e Entry) {
+	if e.ID == "" || strings.TrimSpace(e.Name) == "" {
+		return
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if i, ok := ix.byID[e.ID]; ok {
+		old := &ix.entries[i]
+		old.Weight += e.Weight
+		if !old.Expires.IsZero() && (e.Expires.IsZero() || e.Expires.After(old.Expires)) {
+			old.Expires = e.Expires
+		}
+		return
+	}
+	if ix.added++; ix.added >= sweepEvery {
+		ix.sweep(time.Now())
+	}
+	ix.insert(e)
+}
+
+// sweep rebuilds the index without its expired entries.
+func (ix *Index) sweep(now time.Time) {
+	ix.added = 0
+	live := make([]Entry, 0, len(ix.entries))
+	for _, e := range ix.entries {
+		if !e.expired(now) {
+			live = append(live, e)
+		}
+	}
+	if len(live) == len(ix.entries) {
+		return
+	}
+	ix.reset()
+	for _, e := range live {
+		ix.insert(e)
+	}
+}
+
+func (ix *Index) insert(e Entry) {
+	pos := len(ix.entries)
+	ix.entries = append(ix.entries, e)
+	ix.byID[e.ID] = pos
+
+	name := Normalize(e.Name)
+	ix.names[name] = append(ix.names[name], pos)
+	for _, w := range uniq(strings.Fields(name)) {
+		if _, ok := ix.wordRefs[w]; !ok {
+			ix.pending = append(ix.pending, w)
+		}
+		ix.wordRefs[w] = append(ix.wordRefs[w], pos)
+	}
+	for _, t := range uniq(trigrams(name)) {
+		ix.trigrams[t] = append(ix.trigrams[t], pos)
+	}
+}
+
+// Len reports how many entries are indexed, expired ones not yet swept
+// included.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.entries)
+}
+
+// Has reports whether an entry is named name, ignoring case and
+// punctuation.
+func (ix *Index) Has(name string) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	now := time.Now()
+	for _, pos := range ix.names[Normalize(name)] {
+		if !ix.entries[pos].expired(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggest returns up to limit entries ranked for the partial query q.
+func (ix *Index) Suggest(q string, limit int) []Suggestion {
+	q = Normalize(q)
+	if q == "" || limit <= 0 {
+		return nil
+	}
+	ix.mergePending()
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	qWords := strings.Fields(q)
+	candidates := map[int]bool{}
+	// every query word must prefix some word of the name, so candidates come
+	// from the query word with the fewest matching entries
+	var best []string
+	bestRefs := -1
+	for _, qw := range qWords {
+		words, refs := ix.prefixed(qw)
+		if bestRefs < 0 || refs < bestRefs {
+			best, bestRefs = words, refs
+		}
+	}
+	for _, w := range best {
+		for _, pos := range ix.wordRefs[w] {
+			if len(candidates) >= maxCandidates {
+				break
+			}
+			candidates[pos] = true
+		}
+	}
+	qTrigrams := uniq(trigrams(q))
+	if len(candidates) < limit {
+		for _, t := range qTrigrams {
+			for _, pos := range ix.trigrams[t] {
+				if len(candidates) >= maxCandidates {
+					break
+				}
+				candidates[pos] = true
+			}
+		}
+	}
+
+	var out []Suggestion
+	now := time.Now()
+	for pos := range candidates {
+		e := ix.entries[pos]
+		if e.expired(now) {
+			continue
+		}
+		score := ix.score(Normalize(e.Name), q, qWords, qTrigrams)
+		if score <= 0 {
+			continue
+		}
+		score += math.Log1p(e.Weight) * 0.1
+		out = append(out, Suggestion{Entry: e, Score: math.Round(score*1000) / 1000})
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Score != out[b].Score {
+			return out[a].Score > out[b].Score
+		}
+		if len(out[a].Name) != len(out[b].Name) {
+			return len(out[a].Name) < len(out[b].Name)
+		}
+		return out[a].ID < out[b].ID
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// prefixed returns the indexed words starting with prefix and how many
+// entries they reference in total.
+func (ix *Index) prefixed(prefix string) ([]string, int) {
+	lo := sort.SearchStrings(ix.words, prefix)
+	hi, refs := lo, 0
+	for hi < len(ix.words) && strings.HasPrefix(ix.words[hi], prefix) {
+		refs += len(ix.wordRefs[ix.words[hi]])
+		hi++
+	}
+	return ix.words[lo:hi], refs
+}
+
+// score ranks a whole-name prefix above word prefixes, and word prefixes
+// above fuzzy trigram overlap.
+func (ix *Index) score(name, q string, qWords, qTrigrams []string) float64 {
+	if strings.HasPrefix(name, q) {
+		return 3
+	}
+	words := strings.Fields(name)
+	all := true
+	for _, qw := range qWords {
+		hit := false
+		for _, w := range words {
+			if strings.HasPrefix(w, qw) {
+				hit = true
+				break
+			}
+		}
+		if !hit {
+			all = false
+			break
+		}
+	}
+	if all {
+		return 2
+	}
+
+	nameTrigrams := map[string]bool{}
+	for _, t := range trigrams(name) {
+		nameTrigrams[t] = true
+	}
+	shared := 0
+	for _, t := range qTrigrams {
+		if nameTrigrams[t] {
+			shared++
+		}
+	}
+	sim := float64(shared) / float64(len(qTrigrams))
+	if sim < 0.4 {
+		return 0
+	}
+	return sim
+}
+
+// mergePending moves newly added words into the sorted list before a query.
+// A few words are inserted in place; bulk loads are sorted once.
+func (ix *Index) mergePending() {
+	ix.mu.RLock()
+	n := len(ix.pending)
+	ix.mu.RUnlock()
+	if n == 0 {
+		return
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if len(ix.pending) > 64 {
+		ix.words = append(ix.words, ix.pending...)
+		sort.Strings(ix.words)
+	} else {
+		for _, w := range ix.pending {
+			i := sort.SearchStrings(ix.words, w)
+			ix.words = append(ix.words, "")
+			copy(ix.words[i+1:], ix.words[i:])
+			ix.words[i] = w
+		}
+	}
+	ix.pending = ix.pending[:0]
+}
+
+// Normalize lowercases s and replaces punctuation and underscores with
+// single spaces, so "Apple_Pie," and "apple pie" index the same way.
+func Normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func trigrams(s string) []string {
+	var out []string
+	for _, w := range strings.Fields(s) {
+		r := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			out = append(out, string(r[i:i+3]))
+		}
+	}
+	return out
+}
+
+func uniq(in []string) []string {
+	seen := map[string]bool{}
+	out := in[:0:0]
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package suggest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func testIndex() *Index {
+	ix := NewIndex()
+	for _, e := range []Entry{
+		{ID: "1", Name: "Apple pie", Source: "usda"},
+		{ID: "2", Name: "Apple", Source: "usda"},
+		{ID: "3", Name: "Pineapple juice", Source: "usda"},
+		{ID: "4", Name: "Green apple, raw", Source: "usda"},
+		{ID: "5", Name: "Banana bread", Source: "usda"},
+	} {
+		ix.Add(e)
+	}
+	return ix
+}
+
+func ids(out []Suggestion) []string {
+	var ids []string
+	for _, s := range out {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func TestSuggest(t *testing.T) {
+	ix := testIndex()
+	tests := []struct {
+		q     string
+		limit int
+		want  []string
+	}{
+		// whole-name prefixes first, shorter names breaking ties
+		{"app", 10, []string{"2", "1", "4"}},
+		{"apple p", 10, []string{"1"}},
+		{"raw green", 10, []string{"4"}},
+		{"APPLE_PIE", 10, []string{"1"}},
+		{"app", 1, []string{"2"}},
+		// trigrams catch typos
+		{"bannana", 10, []string{"5"}},
+		{"", 10, nil},
+		{"app", 0, nil},
+	}
+	for _, tt := range tests {
+		// fuzzy matches may fill the rest of the limit after the wanted ones
+		got := ids(ix.Suggest(tt.q, tt.limit))
+		if len(tt.want) > 0 && len(got) > len(tt.want) {
+			got = got[:len(tt.want)]
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Suggest(%q, %d) = %v, want %v", tt.q, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestAddWeight(t *testing.T) {
+	ix := NewIndex()
+	ix.Add(Entry{ID: "a", Name: "Cheddar cheese"})
+	ix.Add(Entry{ID: "b", Name: "Cheddar crackers"})
+	ix.Add(Entry{ID: "b", Name: "Cheddar crackers", Weight: 10})
+	if ix.Len() != 2 {
+		t.Fatalf("Len = %d, want 2 after re-adding an entry", ix.Len())
+	}
+	if got := ids(ix.Suggest("ched", 2)); fmt.Sprint(got) != "[b a]" {
+		t.Errorf("Suggest = %v, want the weighted entry first", got)
+	}
+	ix.Add(Entry{Name: "No ID"})
+	ix.Add(Entry{ID: "c", Name: "  "})
+	if ix.Len() != 2 {
+		t.Errorf("Len = %d, want entries without an ID or name skipped", ix.Len())
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	ix := NewIndex()
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	ix.Add(Entry{ID: "old", Name: "Old cookie", Expires: past})
+	ix.Add(Entry{ID: "new", Name: "New cookie", Expires: future})
+	ix.Add(Entry{ID: "list", Name: "Listed cookie"})
+
+	if got := ids(ix.Suggest("cookie", 10)); len(got) != 2 {
+		t.Errorf("Suggest = %v, want the expired entry skipped", got)
+	}
+	if ix.Has("old cookie") || !ix.Has("NEW COOKIE") || !ix.Has("listed cookie") {
+		t.Error("Has does not follow expiry")
+	}
+
+	// re-adding extends the expiry
+	ix.Add(Entry{ID: "old", Name: "Old cookie", Expires: future})
+	if !ix.Has("old cookie") {
+		t.Error("re-added entry is still expired")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	ix := NewIndex()
+	ix.Add(Entry{ID: "gone", Name: "Gone", Expires: time.Now().Add(-time.Minute)})
+	for i := 0; i < sweepEvery; i++ {
+		ix.Add(Entry{ID: fmt.Sprint(i), Name: fmt.Sprintf("food %d", i)})
+	}
+	if ix.Len() != sweepEvery {
+		t.Errorf("Len = %d, want %d after the expired entry is swept", ix.Len(), sweepEvery)
+	}
+	if got := ids(ix.Suggest("food 999", 1)); fmt.Sprint(got) != "[999]" {
+		t.Errorf("Suggest after sweep = %v, want [999]", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("  Apple_Pie, (Baked)  "); got != "apple pie baked" {
+		t.Errorf("Normalize = %q", got)
+	}
+}
@@ -0,0 +1,229 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Canonical unit spellings used in every response.
+const (
+	Gram       = "g"
+	Milligram  = "mg"
+	Microgram  = "µg"
+	Kilogram   = "kg"
+	Ounce      = "oz"
+	Pound      = "lb"
+	Kcal       = "kcal"
+	Kilojoule  = "kJ"
+	IU         = "IU"
+	Milliliter = "ml"
+	Liter      = "l"
+	Cup        = "cup"
+	Tablespoon = "tbsp"
+	Teaspoon   = "tsp"
+	FluidOunce = "fl oz"
+)
+
+var spellings = map[string]string{
+	"g": Gram, "gr": Gram, "grm": Gram, "gram": Gram, "grams": Gram,
+	"mg": Milligram, "milligram": Milligram, "milligrams": Milligram,
+	"ug": Microgram, "µg": Microgram, "μg": Microgram, "mcg": Microgram, "microgram": Microgram, "micrograms": Microgram,
+	"kg": Kilogram, "kilogram": Kilogram, "kilograms": Kilogram,
+	"oz": Ounce, "ounce": Ounce, "ounces": Ounce,
+	"lb": Pound, "lbs": Pound, "pound": Pound, "pounds": Pound,
+	"kcal": Kcal, "cal": Kcal, "calories": Kcal, "calorie": Kcal,
+	"kj": Kilojoule, "kilojoule": Kilojoule, "kilojoules": Kilojoule,
+	"iu": IU, "ui": IU,
+	"ml": Milliliter, "mlt": Milliliter, "milliliter": Milliliter, "millilitre": Milliliter, "milliliters": Milliliter, "millilitres": Milliliter,
+	"l": Liter, "liter": Liter, "litre": Liter, "liters": Liter, "litres": Liter,
+	"cup": Cup, "cups": Cup,
+	"tbsp": Tablespoon, "tbs": Tablespoon, "tablespoon": Tablespoon, "tablespoons": Tablespoon,
+	"tsp": Teaspoon, "teaspoon": Teaspoon, "teaspoons": Teaspoon,
+	"fl oz": FluidOunce, "fl. oz": FluidOunce, "floz": FluidOunce, "fluid ounce": FluidOunce, "fluid ounces": FluidOunce,
+}
+
+// grams is the weight of one unit of each mass unit.
+var grams = map[string]float64{
+	Kilogram:  1000,
+	Gram:      1,
+	Milligram: 1e-3,
+	Microgram: 1e-6,
+	Ounce:     28.3495,
+	Pound:     453.592,
+}
+
+// milliliters is the volume of one unit of each volume unit. Household
+// measures are US customary.
+var milliliters = map[string]float64{
+	Liter:      1000,
+	Milliliter: 1,
+	Cup:        236.588,
+	Tablespoon: 14.7868,
+	Teaspoon:   4.92892,
+	FluidOunce: 29.5735,
+}
+
+const kJPerKcal = 4.184
+
+// SaltPerSodium is the mass of salt carrying one unit of sodium.
+const SaltPerSodium = 2.5
+
+// Normalize maps provider spellings such as "G", "MG", "UG", "mcg" or "KJ"
+// to the canonical spelling. Unknown units are returned lowercased.
+func Normalize(unit string) string {
+	u := strings.ToLower(strings.TrimSpace(unit))
+	if c, ok := spellings[u]; ok {
+		return c
+	}
+	return u
+}
+
+// IsMass reports whether unit is a unit of weight.
+func IsMass(unit string) bool {
+	_, ok := grams[Normalize(unit)]
+	return ok
+}
+
+// IsVolume reports whether unit is a unit of volume, metric or household.
+func IsVolume(unit string) bool {
+	_, ok := milliliters[Normalize(unit)]
+	return ok
+}
+
+// Milliliters converts a volume such as 2 cups to milliliters.
+func Milliliters(amount float64, unit string) (float64, bool) {
+	ml, ok := milliliters[Normalize(unit)]
+	return amount * ml, ok
+}
+
+// ServingGrams converts a serving amount to grams. Volumes are taken at the
+// density of water, which is how labels usually state liquid servings.
+func ServingGrams(amount float64, unit string) (float64, bool) {
+	switch u := Normalize(unit); {
+	case IsMass(u):
+		g, _ := Convert(amount, u, Gram)
+		return g, true
+	case u == Milliliter:
+		return amount, true
+	case u == Liter:
+		return amount * 1000, true
+	}
+	return 0, false
+}
+
+// Convert converts a value between mass units or between energy units.
+func Convert(value float64, from, to string) (float64, error) {
+	from, to = Normalize(from), Normalize(to)
+	if from == to {
+		return value, nil
+	}
+	if f, ok := grams[from]; ok {
+		if t, ok := grams[to]; ok {
+			return value * f / t, nil
+		}
+	}
+	switch {
+	case from == Kcal && to == Kilojoule:
+		return value * kJPerKcal, nil
+	case from == Kilojoule && to == Kcal:
+		return value / kJPerKcal, nil
+	}
+	return 0, fmt.Errorf("cannot convert %s to %s", from, to)
+}
+
+// iuMicrograms is the µg of each vitamin equal to one IU, vitamin E as
+// natural alpha-tocopherol. Vitamin A has no single factor: an IU is 0.3 µg
+// RAE of retinol but 0.05 µg RAE of beta-carotene, so IU amounts are kept
+// as they are.
+var iuMicrograms = map[string]float64{
+	"vitamin d": 0.025,
+	"vitamin e": 670,
+}
+
+// ConvertNutrient converts a nutrient amount, including IU to and from mass
+// units for vitamins D and E.
+func ConvertNutrient(nutrient string, value float64, from, to string) (float64, error) {
+	from, to = Normalize(from), Normalize(to)
+	if from != IU && to != IU {
+		return Convert(value, from, to)
+	}
+	if from == to {
+		return value, nil
+	}
+	perIU, ok := iuMicrograms[vitaminKey(nutrient)]
+	if !ok {
+		return 0, fmt.Errorf("no IU factor for %q", nutrient)
+	}
+	if from == IU {
+		return Convert(value*perIU, Microgram, to)
+	}
+	ug, err := Convert(value, from, Microgram)
+	if err != nil {
+		return 0, err
+	}
+	return ug / perIU, nil
+}
+
+// SodiumToSalt returns the salt equivalent of a sodium amount, same unit.
+func SodiumToSalt(sodium float64) float64 { return sodium * SaltPerSodium }
+
+// SaltToSodium returns the sodium in a salt amount, same unit.
+func SaltToSodium(salt float64) float64 { return salt / SaltPerSodium }
+
+// CanonicalUnit is the unit a nutrient is reported in. Names are matched
+// loosely so USDA ("Sodium, Na"), Nutritionix and Open Food Facts
+// ("Vitamin A") spellings agree.
+func CanonicalUnit(nutrient, unit string) string {
+	name := strings.ToLower(nutrient)
+	switch {
+	case strings.HasPrefix(name, "energy"):
+		return Kcal
+	case strings.HasPrefix(name, "vitamin a"), strings.HasPrefix(name, "vitamin d"),
+		strings.HasPrefix(name, "vitamin k"), strings.HasPrefix(name, "vitamin b-12"),
+		strings.HasPrefix(name, "vitamin b12"), strings.HasPrefix(name, "folate"),
+		strings.HasPrefix(name, "folic"), strings.HasPrefix(name, "selenium"),
+		strings.HasPrefix(name, "iodine"), strings.HasPrefix(name, "biotin"),
+		strings.HasPrefix(name, "chromium"), strings.HasPrefix(name, "molybdenum"),
+		strings.HasPrefix(name, "carotene"), strings.HasPrefix(name, "lycopene"),
+		strings.HasPrefix(name, "lutein"), strings.HasPrefix(name, "retinol"):
+		return Microgram
+	case strings.HasPrefix(name, "sodium"), strings.HasPrefix(name, "potassium"),
+		strings.HasPrefix(name, "calcium"), strings.HasPrefix(name, "iron"),
+		strings.HasPrefix(name, "magnesium"), strings.HasPrefix(name, "phosph"),
+		strings.HasPrefix(name, "zinc"), strings.HasPrefix(name, "copper"),
+		strings.HasPrefix(name, "manganese"), strings.HasPrefix(name, "cholesterol"),
+		strings.HasPrefix(name, "caffeine"), strings.HasPrefix(name, "vitamin c"),
+		strings.HasPrefix(name, "vitamin e"), strings.HasPrefix(name, "vitamin b"),
+		strings.HasPrefix(name, "thiamin"), strings.HasPrefix(name, "riboflavin"),
+		strings.HasPrefix(name, "niacin"), strings.HasPrefix(name, "pantothenic"),
+		strings.HasPrefix(name, "choline"):
+		return Milligram
+	}
+	u := Normalize(unit)
+	if _, mass := grams[u]; mass || u == "" || u == IU {
+		return Gram
+	}
+	return u
+}
+
+// Canonicalize converts a nutrient amount to its canonical unit. Amounts
+// that cannot be converted keep their normalized unit.
+func Canonicalize(nutrient string, value float64, unit string) (float64, string) {
+	unit = Normalize(unit)
+	to := CanonicalUnit(nutrient, unit)
+	v, err := ConvertNutrient(nutrient, value, unit, to)
+	if err != nil {
+		return value, unit
+	}
+	return v, to
+}
+
+func vitaminKey(nutrient string) string {
+	name := strings.ToLower(nutrient)
+	for key := range iuMicrograms {
+		if strings.HasPrefix(name, key) {
+			return key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"G": Gram, " MG ": Milligram, "UG": Microgram, "mcg": Microgram, "μg": Microgram,
+		"KJ": Kilojoule, "Cal": Kcal, "IU": IU, "Tbsp": Tablespoon, "Fl Oz": FluidOunce,
+		"Serving": "serving",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{1, "kg", "g", 1000},
+		{250, "mg", "g", 0.25},
+		{1, "g", "µg", 1e6},
+		{1, "oz", "g", 28.3495},
+		{100, "kcal", "kJ", 418.4},
+		{418.4, "KJ", "kcal", 100},
+		{5, "g", "G", 5},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to)
+		if err != nil || !near(got, tt.want) {
+			t.Errorf("Convert(%v, %s, %s) = %v, %v; want %v", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+	for _, pair := range [][2]string{{"g", "kcal"}, {"ml", "g"}, {"IU", "µg"}} {
+		if _, err := Convert(1, pair[0], pair[1]); err == nil {
+			t.Errorf("Convert %s to %s succeeded", pair[0], pair[1])
+		}
+	}
+}
+
+func TestConvertNutrient(t *testing.T) {
+	tests := []struct {
+		nutrient string
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{"Vitamin D (D2 + D3)", 400, "IU", "µg", 10},
+		{"Vitamin E (alpha-tocopherol)", 10, "mg", "IU", 10000.0 / 670},
+		{"Calcium, Ca", 1, "g", "mg", 1000},
+	}
+	for _, tt := range tests {
+		got, err := ConvertNutrient(tt.nutrient, tt.value, tt.from, tt.to)
+		if err != nil || !near(got, tt.want) {
+			t.Errorf("ConvertNutrient(%q, %v, %s, %s) = %v, %v; want %v", tt.nutrient, tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+	if _, err := ConvertNutrient("Vitamin C", 1, "IU", "mg"); err == nil {
+		t.Error("IU of vitamin C converted")
+	}
+	if _, err := ConvertNutrient("Vitamin A, RAE", 1000, "IU", "µg"); err == nil {
+		t.Error("IU of vitamin A converted to RAE")
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		nutrient string
+		value    float64
+		unit     string
+		want     float64
+		wantUnit string
+	}{
+		{"Sodium, Na", 0.4, "G", 400, Milligram},
+		{"Energy", 1000, "kJ", 1000 / kJPerKcal, Kcal},
+		{"Vitamin D", 200, "IU", 5, Microgram},
+		{"Protein", 5000, "mg", 5, Gram},
+		{"Folate, DFE", 0.1, "mg", 100, Microgram},
+		// amounts that cannot be converted keep their unit
+		{"Sodium", 3, "serving", 3, "serving"},
+	}
+	for _, tt := range tests {
+		got, unit := Canonicalize(tt.nutrient, tt.value, tt.unit)
+		if !near(got, tt.want) || unit != tt.wantUnit {
+			t.Errorf("Canonicalize(%q, %v, %q) = %v %s, want %v %s", tt.nutrient, tt.value, tt.unit, got, unit, tt.want, tt.wantUnit)
+		}
+	}
+}
+
+func TestSalt(t *testing.T) {
+	if got := SodiumToSalt(400); got != 1000 {
+		t.Errorf("SodiumToSalt(400) = %v", got)
+	}
+	if got := SaltToSodium(1); got != 0.4 {
+		t.Errorf("SaltToSodium(1) = %v", got)
+	}
+}
+
+func TestVolume(t *testing.T) {
+	for _, u := range []string{"ml", "L", "cups", "tablespoon", "tsp", "fl oz"} {
+		if !IsVolume(u) || IsMass(u) {
+			t.Errorf("%q is not a volume only", u)
+		}
+	}
+	for _, u := range []string{"g", "oz", "lb"} {
+		if IsVolume(u) || !IsMass(u) {
+			t.Errorf("%q is not a mass only", u)
+		}
+	}
+	if ml, ok := Milliliters(2, "cups"); !ok || !near(ml, 473.176) {
+		t.Errorf("Milliliters(2, cups) = %v, %v", ml, ok)
+	}
+	if _, ok := Milliliters(1, "slice"); ok {
+		t.Error("slice converted to milliliters")
+	}
+}
+
+func TestServingGrams(t *testing.T) {
+	tests := []struct {
+		amount float64
+		unit   string
+		want   float64
+		ok     bool
+	}{
+		{30, "g", 30, true},
+		{1, "oz", 28.3495, true},
+		{250, "mL", 250, true},
+		{0.5, "l", 500, true},
+		{1, "slice", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ServingGrams(tt.amount, tt.unit)
+		if ok != tt.ok || !near(got, tt.want) {
+			t.Errorf("ServingGrams(%v, %q) = %v, %v; want %v, %v", tt.amount, tt.unit, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package vision
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+)
+
+// Box is a region of an image in pixel coordinates.
+type Box struct {
+	XMin int `json:"xmin"`
+	YMin int `json:"ymin"`
+	XMax int `json:"xmax"`
+	YMax int `json:"ymax"`
+}
+
+// Region is one detected item on a plate together with its cropped image.
+type Region struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+	Box   Box     `json:"box"`
+	Image []byte  `json:"-"`
+}
+
+// ErrInvalidImage is returned, wrapped, for images that are not a decodable
+// JPEG or PNG.
+var ErrInvalidImage = errors.New("invalid image")
+
+// Detector finds the separate food items in an image.
+type Detector interface {
+	Detect(img []byte) ([]Region, error)
+}
+
+// NewDetector returns the detector for the configured backend. Unknown or
+// empty backends fall back to the local stub.
+func NewDetector(backend, model, apiKey string) Detector {
+	switch backend {
+	case "huggingface":
+		return &HuggingFaceDetector{Model: model, APIKey: apiKey}
+	default:
+		return StubDetector{}
+	}
+}
+
+// StubDetector treats the whole image as a single region. It lets plate mode
+// run locally without an object-detection backend.
+type StubDetector struct{}
+
+func (StubDetector) Detect(img []byte) ([]Region, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	return []Region{{
+		Label: "plate",
+		Score: 1,
+		Box:   Box{XMax: cfg.Width, YMax: cfg.Height},
+		Image: img,
+	}}, nil
+}
+
+// HuggingFaceDetector runs an object-detection model (DETR by default) on the
+// Hugging Face inference API and crops every food-like region it returns.
+type HuggingFaceDetector struct {
+	Model  string
+	APIKey string
+}
+
+// foodLabels are the COCO classes worth classifying further. Bowls are kept
+// because soups and rice are usually detected only as their container.
+var foodLabels = map[string]bool{
+	"banana": true, "apple": true, "sandwich": true, "orange": true,
+	"broccoli": true, "carrot": true, "hot dog": true, "pizza": true,
+	"donut": true, "cake": true, "bowl": true,
+}
+
+func (d *HuggingFaceDetector) Detect(img []byte) ([]Region, error) {
+	src, _, err := image.Decode(bytes.NewReader(img))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	req, _ := http.NewRequest("POST", "https://api-inference.huggingface.co/models/"+d.Model, bytes.NewReader(img))
+	req.Header.Set("Authorization", "Bearer "+d.APIKey)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("detection model returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var detections []Region
+	if err := json.NewDecoder(resp.Body).Decode(&detections); err != nil {
+		return nil, err
+	}
+
+	var regions []Region
+	for _, det := range detections {
+		if det.Score < 0.5 || !foodLabels[det.Label] {
+			continue
+		}
+		crop, err := Crop(src, det.Box)
+		if err != nil {
+			continue
+		}
+		det.Image = crop
+		regions = append(regions, det)
+	}
+	return regions, nil
+}
+
+// Crop cuts box out of src and encodes it as JPEG.
+func Crop(src image.Image, box Box) ([]byte, error) {
+	rect := image.Rect(box.XMin, box.YMin, box.XMax, box.YMax).Intersect(src.Bounds())
+	if rect.Empty() {
+		return nil, fmt.Errorf("empty region %v", box)
+	}
+	sub, ok := src.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, fmt.Errorf("image type %T cannot be cropped", src)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, sub.SubImage(rect), &jpeg.Options{Quality: 90}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
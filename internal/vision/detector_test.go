@@ -0,0 +1,70 @@
+package vision
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewDetector(t *testing.T) {
+	if _, ok := NewDetector("huggingface", "facebook/detr-resnet-50", "key").(*HuggingFaceDetector); !ok {
+		t.Error("huggingface backend is not a HuggingFaceDetector")
+	}
+	for _, backend := range []string{"", "stub", "unknown"} {
+		if _, ok := NewDetector(backend, "", "").(StubDetector); !ok {
+			t.Errorf("backend %q is not the stub", backend)
+		}
+	}
+}
+
+func TestStubDetector(t *testing.T) {
+	img := pngImage(t, 64, 48)
+	regions, err := StubDetector{}.Detect(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(regions) != 1 {
+		t.Fatalf("got %d regions, want 1", len(regions))
+	}
+	if want := (Box{XMax: 64, YMax: 48}); regions[0].Box != want {
+		t.Errorf("box = %+v, want %+v", regions[0].Box, want)
+	}
+	if !bytes.Equal(regions[0].Image, img) {
+		t.Error("region image is not the whole image")
+	}
+
+	if _, err := (StubDetector{}).Detect([]byte("not an image")); !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("got %v, want ErrInvalidImage", err)
+	}
+}
+
+func TestCrop(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 80))
+	crop, err := Crop(src, Box{XMin: 10, YMin: 20, XMax: 150, YMax: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(crop))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 90 || cfg.Height != 40 {
+		t.Errorf("crop is %dx%d, want 90x40 clipped to the image", cfg.Width, cfg.Height)
+	}
+
+	if _, err := Crop(src, Box{XMin: 200, YMin: 200, XMax: 300, YMax: 300}); err == nil {
+		t.Error("crop outside the image succeeded")
+	}
+}